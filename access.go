@@ -1,15 +1,13 @@
 package godynamo
 
 import (
-	"fmt"
+	"errors"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
-	"github.com/gofrs/uuid"
 	"reflect"
 	"strings"
-	"time"
 )
 
 func (a *DynamoAccess) typeToScalarType(Type string) (dynamodb.ScalarAttributeType, error) {
@@ -35,51 +33,38 @@ func (a *DynamoAccess) typeToScalarType(Type string) (dynamodb.ScalarAttributeTy
 
 func (a *DynamoAccess) tableBuilder(item interface{}, table *dynamodb.CreateTableInput) error {
 	var err error
-	v := reflect.ValueOf(item)
-	t := v.Type()
 
-	if v.Elem().Type().String() == "reflect.rtype" {
-		t = item.(reflect.Type)
-	}
-
-	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
-		t = t.Elem()
-	}
-
-	for i := 0; i < t.NumField(); i++ {
-		if t.Field(i).Type.Kind() == reflect.Struct && t.Field(i).Name == "Model" {
-			if err := a.tableBuilder(t.Field(i).Type, table); err != nil {
-				return err
-			}
-		}
-
-		dynamoTag, ok := t.Field(i).Tag.Lookup("godynamo")
+	fields, _ := modelFields(reflect.TypeOf(item))
+	for _, field := range fields {
+		dynamoTag, ok := field.Tag.Lookup("godynamo")
 		if !ok {
 			continue
 		}
 
-		jsonTag, ok := t.Field(i).Tag.Lookup("json")
-		if !ok {
-			continue
-		}
+		name := field.attribute
 
 		dynamoFuncs := strings.Split(dynamoTag, ",")
 		for _, dynamoFunc := range dynamoFuncs {
 			var gsiB, lsiB, atributeExist bool
 			index := 0
 
+			// ttl is not part of any key schema
+			if dynamoFunc == "ttl" {
+				continue
+			}
+
 			for _, attributeDefinition := range table.AttributeDefinitions {
-				if *attributeDefinition.AttributeName == jsonTag {
+				if *attributeDefinition.AttributeName == name {
 					atributeExist = true
 				}
 			}
 
 			if !atributeExist {
 				attribute := dynamodb.AttributeDefinition{
-					AttributeName: aws.String(jsonTag),
+					AttributeName: aws.String(name),
 				}
 
-				attribute.AttributeType, err = a.typeToScalarType(t.Field(i).Type.String())
+				attribute.AttributeType, err = a.typeToScalarType(field.Type.Kind().String())
 				if err != nil {
 					return err
 				}
@@ -131,18 +116,12 @@ func (a *DynamoAccess) tableBuilder(item interface{}, table *dynamodb.CreateTabl
 						},
 					}
 
-					for _, key := range table.KeySchema {
-						if key.KeyType == dynamodb.KeyTypeHash {
-							localSecondaryIndex.KeySchema = append([]dynamodb.KeySchemaElement{key}, localSecondaryIndex.KeySchema...)
-						}
-					}
-
 					table.LocalSecondaryIndexes = append(table.LocalSecondaryIndexes, localSecondaryIndex)
 				}
 			}
 
 			elem := dynamodb.KeySchemaElement{
-				AttributeName: aws.String(jsonTag),
+				AttributeName: aws.String(name),
 			}
 
 			switch dynamoFunc {
@@ -178,50 +157,96 @@ func (a *DynamoAccess) tableBuilder(item interface{}, table *dynamodb.CreateTabl
 		}
 	}
 
+	// local secondary indexes share hash key of the table, which may be declared after them
+	for _, key := range table.KeySchema {
+		if key.KeyType != dynamodb.KeyTypeHash {
+			continue
+		}
+		for i := range table.LocalSecondaryIndexes {
+			table.LocalSecondaryIndexes[i].KeySchema = append([]dynamodb.KeySchemaElement{key}, table.LocalSecondaryIndexes[i].KeySchema...)
+		}
+	}
+
 	return nil
 }
 
+// tableInput, builds whole create table request of given model
+func (a *DynamoAccess) tableInput(item interface{}) (*dynamodb.CreateTableInput, error) {
+	if err := ValidateModel(item); err != nil {
+		return nil, err
+	}
+
+	table := &dynamodb.CreateTableInput{}
+	var err error
+
+	table.TableName, _, err = a.tableName(item)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.tableBuilder(item, table); err != nil {
+		return nil, err
+	}
+
+	table.ProvisionedThroughput = &dynamodb.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(10),
+		WriteCapacityUnits: aws.Int64(10),
+	}
+
+	settings := a.settings(item)
+	table.StreamSpecification = settings.streamSpecification()
+	table.SSESpecification = settings.sseSpecification()
+
+	return table, nil
+}
+
 func (a *DynamoAccess) CreateTables(items ...interface{}) []error {
 	var errors []error
 	for _, item := range items {
-		table := &dynamodb.CreateTableInput{}
-		var err error
-
-		table.TableName, _, err = a.tableName(item)
+		op, _, err := a.operation(OperationCreateTable, item)
 		if err != nil {
 			errors = append(errors, err)
+			continue
 		}
 
-		if err := a.tableBuilder(item, table); err != nil {
+		if err := a.intercept(op, a.createTable); err != nil {
 			errors = append(errors, err)
 		}
+	}
 
-		table.ProvisionedThroughput = &dynamodb.ProvisionedThroughput{
-			ReadCapacityUnits:  aws.Int64(10),
-			WriteCapacityUnits: aws.Int64(10),
-		}
+	return errors
+}
 
-		// Send the request, and get the response or error back
-		if _, err = a.svc.CreateTableRequest(table).Send(); err != nil {
-			errors = append(errors, err)
-		}
+func (a *DynamoAccess) createTable(op *Operation) error {
+	table, err := a.tableInput(op.Item)
+	if err != nil {
+		return err
 	}
+	table.TableName = aws.String(op.Table)
 
-	return errors
+	// Send the request, and get the response or error back
+	var result *dynamodb.CreateTableOutput
+	if err := a.send(op, func() (err error) {
+		result, err = a.svc.CreateTableRequest(table).Send()
+		return err
+	}); err != nil {
+		return err
+	}
+
+	return a.configureTable(op.Item, result.TableDescription)
 }
 
 func (a *DynamoAccess) DropTables(items ...interface{}) []error {
 	var errors []error
 
 	for _, item := range items {
-		tableName, _, err := a.tableName(item)
+		op, _, err := a.operation(OperationDropTable, item)
 		if err != nil {
 			errors = append(errors, err)
+			continue
 		}
 
-		if _, err := a.svc.DeleteTableRequest(&dynamodb.DeleteTableInput{
-			TableName: tableName,
-		}).Send(); err != nil {
+		if err := a.intercept(op, a.dropTable); err != nil {
 			errors = append(errors, err)
 		}
 	}
@@ -229,128 +254,260 @@ func (a *DynamoAccess) DropTables(items ...interface{}) []error {
 	return errors
 }
 
+func (a *DynamoAccess) dropTable(op *Operation) error {
+	return a.send(op, func() error {
+		_, err := a.svc.DeleteTableRequest(&dynamodb.DeleteTableInput{
+			TableName: aws.String(op.Table),
+		}).Send()
+		return err
+	})
+}
+
 // Create, given item si created in db, with new id
 func (a *DynamoAccess) Create(item interface{}) error {
-	tableName, _, err := a.tableName(item)
+	op, _, err := a.operation(OperationCreate, item)
 	if err != nil {
 		return err
 	}
 
+	return a.intercept(op, a.create)
+}
+
+func (a *DynamoAccess) create(op *Operation) error {
+	item := op.Item
+
+	if err := beforeCreate(item); err != nil {
+		return err
+	}
+
 	av, err := dynamodbattribute.MarshalMap(item)
 	if err != nil {
 		return err
 	}
 
-	// add uuid
-	if av["id"].NULL != nil && *av["id"].NULL {
-		id, err := uuid.NewV4()
+	config := a.modelConfig(item)
+
+	// add id
+	if config.needsId(av) {
+		id, err := config.newID()
 		if err != nil {
 			return err
 		}
-		av["id"] = dynamodb.AttributeValue{
-			S: aws.String(id.String()),
+		av[config.IdAttribute] = dynamodb.AttributeValue{
+			S: aws.String(id),
 		}
 	}
 
 	// add timestamps
-	timeNow := fmt.Sprint(time.Now().Unix())
-	av["created"] = dynamodb.AttributeValue{
-		N: aws.String(timeNow),
+	timeNow := now(a.clock)
+	if config.CreatedAttribute != "" {
+		av[config.CreatedAttribute] = config.timestamp(timeNow)
 	}
-	av["updated"] = dynamodb.AttributeValue{
-		N: aws.String(timeNow),
+	if config.UpdatedAttribute != "" {
+		av[config.UpdatedAttribute] = config.timestamp(timeNow)
 	}
 
-	if _, err := a.svc.PutItemRequest(&dynamodb.PutItemInput{
-		Item:      av,
-		TableName: tableName,
-	}).Send(); err != nil {
+	var result *dynamodb.PutItemOutput
+	if err := a.send(op, func() (err error) {
+		result, err = a.svc.PutItemRequest(&dynamodb.PutItemInput{
+			Item:                   av,
+			TableName:              aws.String(op.Table),
+			ReturnConsumedCapacity: a.returnConsumedCapacity(),
+		}).Send()
+		return err
+	}); err != nil {
 		return err
 	}
+	a.consumed(op, result.ConsumedCapacity)
+	a.uncache(op, av)
 
-	return dynamodbattribute.UnmarshalMap(av, item)
+	if err := dynamodbattribute.UnmarshalMap(av, item); err != nil {
+		return err
+	}
+
+	return afterCreate(item)
 }
 
 // Update, given item is updated
 func (a *DynamoAccess) Update(item interface{}) error {
-	tableName, _, err := a.tableName(item)
+	op, _, err := a.operation(OperationUpdate, item)
 	if err != nil {
 		return err
 	}
 
+	return a.intercept(op, a.update)
+}
+
+func (a *DynamoAccess) update(op *Operation) error {
+	item := op.Item
+
+	if err := beforeUpdate(item); err != nil {
+		return err
+	}
+
 	av, err := dynamodbattribute.MarshalMap(item)
 	if err != nil {
 		return err
 	}
 
 	// add timestamp
-	av["updated"] = dynamodb.AttributeValue{
-		N: aws.String(fmt.Sprint(time.Now().Unix())),
+	if config := a.modelConfig(item); config.UpdatedAttribute != "" {
+		av[config.UpdatedAttribute] = config.timestamp(now(a.clock))
 	}
 
-	if _, err := a.svc.PutItemRequest(&dynamodb.PutItemInput{
-		Item:      av,
-		TableName: tableName,
-	}).Send(); err != nil {
+	var result *dynamodb.PutItemOutput
+	if err := a.send(op, func() (err error) {
+		result, err = a.svc.PutItemRequest(&dynamodb.PutItemInput{
+			Item:                   av,
+			TableName:              aws.String(op.Table),
+			ReturnConsumedCapacity: a.returnConsumedCapacity(),
+		}).Send()
+		return err
+	}); err != nil {
 		return err
 	}
+	a.consumed(op, result.ConsumedCapacity)
+	a.uncache(op, av)
 
 	return dynamodbattribute.UnmarshalMap(av, item)
 }
 
 // Delete, given id of item is deleted
 func (a *DynamoAccess) Delete(item interface{}, key, value string) error {
-	tableName, _, err := a.tableName(item)
+	op, _, err := a.operation(OperationDelete, item)
 	if err != nil {
 		return err
 	}
+	op.Key = map[string]dynamodb.AttributeValue{
+		key: {S: aws.String(value)},
+	}
 
-	if _, err := a.svc.DeleteItemRequest(&dynamodb.DeleteItemInput{
-		TableName: tableName,
-		Key: map[string]dynamodb.AttributeValue{
-			key: {S: aws.String(value)},
-		},
-	}).Send(); err != nil {
+	return a.intercept(op, a.delete)
+}
+
+func (a *DynamoAccess) delete(op *Operation) error {
+	if err := beforeDelete(op.Item); err != nil {
 		return err
 	}
 
+	var result *dynamodb.DeleteItemOutput
+	if err := a.send(op, func() (err error) {
+		result, err = a.svc.DeleteItemRequest(&dynamodb.DeleteItemInput{
+			TableName:              aws.String(op.Table),
+			Key:                    op.Key,
+			ReturnConsumedCapacity: a.returnConsumedCapacity(),
+		}).Send()
+		return err
+	}); err != nil {
+		return err
+	}
+	a.consumed(op, result.ConsumedCapacity)
+	a.uncache(op, nil)
+
 	return nil
 }
 
 // SoftDelete, given id of item is mark as deleted in time stamp deleted
 func (a *DynamoAccess) SoftDelete(item interface{}, key, value string) error {
-	if err := a.GetItem(item, key, value); err != nil {
+	op, _, err := a.operation(OperationSoftDelete, item)
+	if err != nil {
 		return err
 	}
+	op.Key = map[string]dynamodb.AttributeValue{
+		key: {S: aws.String(value)},
+	}
+
+	return a.intercept(op, a.softDelete)
+}
+
+// softDelete, item is loaded by get item operation, which passes the interceptors as well
+func (a *DynamoAccess) softDelete(op *Operation) error {
+	item := op.Item
+
+	config := a.modelConfig(item)
+	if config.DeletedAttribute == "" {
+		return ErrNoSoftDelete
+	}
+
+	// item is read from db, not from cache
+	a.uncache(op, nil)
 
-	tableName, _, err := a.tableName(item)
+	get := *op
+	get.Name = OperationGetItem
+	err := a.intercept(&get, a.getItem)
+	op.Capacity = append(op.Capacity, get.Capacity...)
+	op.Retries += get.Retries
 	if err != nil {
 		return err
 	}
 
+	if err := beforeDelete(item); err != nil {
+		return err
+	}
+
+	if err := validate(item); err != nil {
+		return err
+	}
+
 	av, err := dynamodbattribute.MarshalMap(item)
 	if err != nil {
 		return err
 	}
 
 	// add timestamp
-	av["deleted"] = dynamodb.AttributeValue{
-		N: aws.String(fmt.Sprint(time.Now().Unix())),
-	}
-
-	if _, err := a.svc.PutItemRequest(&dynamodb.PutItemInput{
-		Item:      av,
-		TableName: tableName,
-	}).Send(); err != nil {
+	av[config.DeletedAttribute] = config.timestamp(now(a.clock))
+
+	var result *dynamodb.PutItemOutput
+	if err := a.send(op, func() (err error) {
+		result, err = a.svc.PutItemRequest(&dynamodb.PutItemInput{
+			Item:                   av,
+			TableName:              aws.String(op.Table),
+			ReturnConsumedCapacity: a.returnConsumedCapacity(),
+		}).Send()
+		return err
+	}); err != nil {
 		return err
 	}
+	a.consumed(op, result.ConsumedCapacity)
+	a.uncache(op, av)
 
 	return dynamodbattribute.UnmarshalMap(av, item)
 }
 
-//Query, find item by given query input
+// Query, find item by given query input, index is selected by attributes of key condition when IndexName is empty
 func (a *DynamoAccess) Query(item interface{}, input RequestInput) error {
-	tableName, slice, err := a.tableName(item)
+	_, err := a.QueryIndex(item, input)
+	return err
+}
+
+// QueryIndex, same as Query, returns name of index the query was sent to, empty for the table,
+// query of model which schema can not be built is sent to the table
+func (a *DynamoAccess) QueryIndex(item interface{}, input RequestInput) (string, error) {
+	op, _, err := a.operation(OperationQuery, item)
+	if err != nil {
+		return "", err
+	}
+	op.Input = input
+
+	if input.IndexName == "" && input.Expr.KeyCondition() != nil {
+		index, err := a.SelectIndex(item, input)
+		if errors.Is(err, ErrNoMatchingIndex) || errors.Is(err, ErrAmbiguousIndex) {
+			return "", err
+		}
+		op.Input.IndexName = index
+	}
+
+	if err := a.intercept(op, a.query); err != nil {
+		return "", err
+	}
+
+	return op.Input.IndexName, nil
+}
+
+func (a *DynamoAccess) query(op *Operation) error {
+	item, input := op.Item, op.Input
+
+	_, slice, err := a.tableName(item)
 	if err != nil {
 		return err
 	}
@@ -360,7 +517,8 @@ func (a *DynamoAccess) Query(item interface{}, input RequestInput) error {
 		ExpressionAttributeValues: input.Expr.Values(),
 		KeyConditionExpression:    input.Expr.KeyCondition(),
 		ScanIndexForward:          aws.Bool(input.ScanIndexForward),
-		TableName:                 tableName,
+		TableName:                 aws.String(op.Table),
+		ReturnConsumedCapacity:    a.returnConsumedCapacity(),
 	}
 
 	if input.Expr.Filter() != nil && *input.Expr.Filter() != "" {
@@ -379,7 +537,16 @@ func (a *DynamoAccess) Query(item interface{}, input RequestInput) error {
 		queryInput.ExclusiveStartKey = input.ExclusiveStartKey
 	}
 
-	result, err := a.svc.QueryRequest(queryInput).Send()
+	var result *dynamodb.QueryOutput
+	if err := a.send(op, func() (err error) {
+		result, err = a.svc.QueryRequest(queryInput).Send()
+		return err
+	}); err != nil {
+		return err
+	}
+	a.consumed(op, result.ConsumedCapacity)
+
+	result.Items, err = a.dropExpired(item, result.Items)
 	if err != nil {
 		return err
 	}
@@ -394,12 +561,16 @@ func (a *DynamoAccess) Query(item interface{}, input RequestInput) error {
 		}
 	}
 
-	return nil
+	if len(result.Items) == 0 {
+		return nil
+	}
+
+	return afterLoad(item)
 }
 
 // GetItem, find item by attribute
 func (a *DynamoAccess) GetItem(item interface{}, key, value string) error {
-	tableName, slice, err := a.tableName(item)
+	op, slice, err := a.operation(OperationGetItem, item)
 	if err != nil {
 		return err
 	}
@@ -408,25 +579,64 @@ func (a *DynamoAccess) GetItem(item interface{}, key, value string) error {
 		return ErrSlice
 	}
 
-	result, err := a.svc.GetItemRequest(&dynamodb.GetItemInput{
-		TableName: tableName,
-		Key: map[string]dynamodb.AttributeValue{
-			key: {S: aws.String(value)},
-		},
-	}).Send()
+	op.Key = map[string]dynamodb.AttributeValue{
+		key: {S: aws.String(value)},
+	}
+
+	return a.intercept(op, a.getItem)
+}
+
+func (a *DynamoAccess) getItem(op *Operation) error {
+	item := op.Item
+
+	found, err := a.readItem(op)
 	if err != nil {
 		return err
 	}
 
-	if err := dynamodbattribute.UnmarshalMap(result.Item, item); err != nil {
+	if err := dynamodbattribute.UnmarshalMap(found, item); err != nil {
 		return err
 	}
 
-	if result.Item["id"].S == nil || (result.Item["deleted"].N != nil && *result.Item["deleted"].N != *aws.String("0")) {
+	if len(found) == 0 || a.modelConfig(item).isDeleted(found) {
 		return ErrNotFound
 	}
 
-	return nil
+	live, err := a.dropExpired(item, []map[string]dynamodb.AttributeValue{found})
+	if err != nil {
+		return err
+	}
+
+	if len(live) == 0 {
+		return ErrNotFound
+	}
+
+	return afterLoad(item)
+}
+
+// readItem, item of the key from cache or db, empty when there is no such item
+func (a *DynamoAccess) readItem(op *Operation) (map[string]dynamodb.AttributeValue, error) {
+	if found, ok := a.cached(op, op.Key); ok {
+		op.Cached = true
+		return found, nil
+	}
+
+	version := a.cacheVersion()
+	var result *dynamodb.GetItemOutput
+	if err := a.send(op, func() (err error) {
+		result, err = a.svc.GetItemRequest(&dynamodb.GetItemInput{
+			TableName:              aws.String(op.Table),
+			Key:                    op.Key,
+			ReturnConsumedCapacity: a.returnConsumedCapacity(),
+		}).Send()
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	a.consumed(op, result.ConsumedCapacity)
+
+	a.cacheItem(op, op.Key, result.Item, version)
+	return result.Item, nil
 }
 
 // GetItem, find item by attribute (key)
@@ -434,44 +644,129 @@ func (a *DynamoAccess) GetItems(item interface{}, key string, values []string) e
 	if len(values) < 1 {
 		return nil
 	}
-	tableName, slice, err := a.tableName(item)
+	op, slice, err := a.operation(OperationGetItems, item)
 	if err != nil {
 		return err
 	}
 	if !slice {
 		return ErrNotSlice
 	}
-	reqItems := make(map[string]dynamodb.KeysAndAttributes)
-	keys := make([]map[string]dynamodb.AttributeValue, 0, len(values))
+	op.Keys = make([]map[string]dynamodb.AttributeValue, 0, len(values))
 	for _, value := range values {
-		keys = append(keys, map[string]dynamodb.AttributeValue{
+		op.Keys = append(op.Keys, map[string]dynamodb.AttributeValue{
 			key: {S: aws.String(value)},
 		})
 	}
-	reqItems[*tableName] = dynamodb.KeysAndAttributes{
+
+	return a.intercept(op, a.getItems)
+}
+
+func (a *DynamoAccess) getItems(op *Operation) error {
+	var items, keys []map[string]dynamodb.AttributeValue
+	for _, key := range op.Keys {
+		if found, ok := a.cached(op, key); ok {
+			op.Cached = true
+			if len(found) != 0 {
+				items = append(items, found)
+			}
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return a.loadItems(op, items)
+	}
+
+	version := a.cacheVersion()
+	reqItems := make(map[string]dynamodb.KeysAndAttributes)
+	reqItems[op.Table] = dynamodb.KeysAndAttributes{
 		Keys: keys,
 	}
 
-	result, err := a.svc.BatchGetItemRequest(&dynamodb.BatchGetItemInput{
-		RequestItems: reqItems,
-	}).Send()
+	var fetched []map[string]dynamodb.AttributeValue
+	err := a.sendBatch(op, func() (int, error) {
+		var result *dynamodb.BatchGetItemOutput
+		if err := a.send(op, func() (err error) {
+			result, err = a.svc.BatchGetItemRequest(&dynamodb.BatchGetItemInput{
+				RequestItems:           reqItems,
+				ReturnConsumedCapacity: a.returnConsumedCapacity(),
+			}).Send()
+			return err
+		}); err != nil {
+			return 0, err
+		}
+		for i := range result.ConsumedCapacity {
+			a.consumed(op, &result.ConsumedCapacity[i])
+		}
+
+		fetched = append(fetched, result.Responses[op.Table]...)
+		reqItems = result.UnprocessedKeys
+		return len(reqItems[op.Table].Keys), nil
+	})
+	// keys left unprocessed after retries are returned by error, found items are loaded anyway
+	if err != nil && !errors.Is(err, ErrUnprocessedItems) {
+		return err
+	}
+
+	unprocessed := map[string]bool{}
+	var unprocessedErr error
 	if err != nil {
+		unprocessedErr = &UnprocessedKeysError{Keys: map[string][]map[string]dynamodb.AttributeValue{
+			op.Table: reqItems[op.Table].Keys,
+		}}
+		for _, key := range reqItems[op.Table].Keys {
+			unprocessed[cacheKey(op.Table, key)] = true
+		}
+	}
+
+	if a.cache != nil {
+		for _, key := range keys {
+			if unprocessed[cacheKey(op.Table, key)] {
+				continue
+			}
+			var found map[string]dynamodb.AttributeValue
+			for _, item := range fetched {
+				if cacheKey(op.Table, primaryKey(op.Model, item)) == cacheKey(op.Table, key) {
+					found = item
+					break
+				}
+			}
+			a.cacheItem(op, key, found, version)
+		}
+	}
+
+	if err := a.loadItems(op, append(items, fetched...)); err != nil {
 		return err
 	}
-	if err := dynamodbattribute.UnmarshalListOfMaps(result.Responses[*tableName], item); err != nil {
+
+	return unprocessedErr
+}
+
+// loadItems, unmarshals items, which are not expired, into item of operation
+func (a *DynamoAccess) loadItems(op *Operation, items []map[string]dynamodb.AttributeValue) error {
+	responses, err := a.dropExpired(op.Item, items)
+	if err != nil {
 		return err
 	}
-	return nil
+	if err := dynamodbattribute.UnmarshalListOfMaps(responses, op.Item); err != nil {
+		return err
+	}
+	return afterLoad(op.Item)
 }
 
 // ScanByAttribute, find item by attribute
-func (a *DynamoAccess) ScanByAttribute(item interface{}, key, value string)  (*dynamodb.ScanOutput, error) {
+func (a *DynamoAccess) ScanByAttribute(item interface{}, key, value string) (*dynamodb.ScanOutput, error) {
 	return a.ScanByFilter(item, expression.Name(key).Equal(expression.Value(value)))
 }
 
-func (a *DynamoAccess) ScanByFilter(item interface{}, filt expression.ConditionBuilder)  (*dynamodb.ScanOutput, error) {
+func (a *DynamoAccess) ScanByFilter(item interface{}, filt expression.ConditionBuilder) (*dynamodb.ScanOutput, error) {
+	if config := a.modelConfig(item); config.DeletedAttribute != "" {
+		filt = filt.And(config.notDeleted())
+	}
+
 	expr, err := expression.NewBuilder().
-		WithFilter(filt.And(expression.Name("deleted").Equal(expression.Value(0)))).
+		WithFilter(filt).
 		Build()
 	if err != nil {
 		return nil, err
@@ -483,18 +778,40 @@ func (a *DynamoAccess) ScanByFilter(item interface{}, filt expression.ConditionB
 }
 
 func (a *DynamoAccess) Scan(item interface{}, input RequestInput) (*dynamodb.ScanOutput, error) {
-	tableName, slice, err := a.tableName(item)
+	op, _, err := a.operation(OperationScan, item)
+	if err != nil {
+		return nil, err
+	}
+	op.Input = input
+
+	reservation, err := a.guardScan(op)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := a.intercept(op, a.scan); err != nil {
+		a.settleScan(reservation, nil)
+		return nil, err
+	}
+
+	result, _ := op.Result.(*dynamodb.ScanOutput)
+	a.settleScan(reservation, result)
+	return result, nil
+}
+
+func (a *DynamoAccess) scan(op *Operation) error {
+	item, input := op.Item, op.Input
+
+	_, slice, err := a.tableName(item)
+	if err != nil {
+		return err
+	}
+
 	scanInput := &dynamodb.ScanInput{
 		ExpressionAttributeNames:  input.Expr.Names(),
 		ExpressionAttributeValues: input.Expr.Values(),
-		TableName:                 tableName,
-	}
-	if err != nil {
-		return nil, err
+		TableName:                 aws.String(op.Table),
+		ReturnConsumedCapacity:    a.returnConsumedCapacity(),
 	}
 
 	if input.Expr.Filter() != nil && *input.Expr.Filter() != "" {
@@ -513,22 +830,37 @@ func (a *DynamoAccess) Scan(item interface{}, input RequestInput) (*dynamodb.Sca
 		scanInput.ExclusiveStartKey = input.ExclusiveStartKey
 	}
 
-	result, err := a.svc.ScanRequest(scanInput).Send()
+	var result *dynamodb.ScanOutput
+	if err := a.send(op, func() (err error) {
+		result, err = a.svc.ScanRequest(scanInput).Send()
+		return err
+	}); err != nil {
+		return err
+	}
+	a.consumed(op, result.ConsumedCapacity)
+
+	result.Items, err = a.dropExpired(item, result.Items)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	result.Count = aws.Int64(int64(len(result.Items)))
+	op.Result = result
 
 	if !slice && len(result.Items) > 0 {
 		if err := dynamodbattribute.UnmarshalMap(result.Items[0], item); err != nil {
-			return nil, err
+			return err
 		}
 	} else {
 		if err := dynamodbattribute.UnmarshalListOfMaps(result.Items, item); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	return result, nil
+	if len(result.Items) > 0 {
+		return afterLoad(item)
+	}
+
+	return nil
 }
 
 // tableName return name of struct, and flag if is slice or not
@@ -1,14 +1,18 @@
 package godynamo
 
 import (
+	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
 	"github.com/aws/aws-sdk-go-v2/aws/defaults"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
+	"github.com/flowup-labs/godynamo/godynamotest"
 	"github.com/stretchr/testify/suite"
 	"strconv"
 	"testing"
+	"time"
 )
 
 type AccessSuite struct {
@@ -35,9 +39,9 @@ func (t *AccessSuite) SetupSuite() {
 
 func (t *AccessSuite) SetupTest() {
 
-	t.access.DropTables(&aaa{}, &ccc{}, &bbb{}, &ddd{}, &fff{}, &eee{}, &user{})
+	t.access.DropTables(&aaa{}, &ccc{}, &bbb{}, &ddd{}, &fff{}, &eee{}, &user{}, &ggg{}, &hhh{}, &iii{}, &kkk{}, &lll{}, &mmm{})
 
-	t.access.CreateTables(&aaa{}, &ccc{}, &bbb{}, &ddd{}, &fff{}, &eee{}, &user{})
+	t.access.CreateTables(&aaa{}, &ccc{}, &bbb{}, &ddd{}, &fff{}, &eee{}, &user{}, &ggg{}, &hhh{}, &iii{}, &kkk{}, &lll{}, &mmm{})
 }
 
 func (t *AccessSuite) TestReflect() {
@@ -329,6 +333,34 @@ func (t *AccessSuite) TestGetItems() {
 	t.Equal(ErrNotSlice, t.access.GetItems(&wrong, "id", []string{"1"}))
 }
 
+func (t *AccessSuite) TestBatchGet() {
+	var bIds, cIds []string
+	for i := 0; i < 120; i++ {
+		b := &bbb{Ba: strconv.Itoa(i)}
+		t.Nil(t.access.Create(b))
+		bIds = append(bIds, b.Id)
+	}
+	for i := 0; i < 15; i++ {
+		c := &ccc{Ca: strconv.Itoa(i)}
+		t.Nil(t.access.Create(c))
+		cIds = append(cIds, c.Id)
+	}
+
+	var bbbs, moreBbbs []bbb
+	var cccs []ccc
+	t.Nil(t.access.BatchGet().
+		Add(&bbbs, "id", bIds...).
+		Add(&cccs, "id", append(cIds, "missing")...).
+		Add(&moreBbbs, "id", bIds[:2]...).
+		Run())
+	t.Len(bbbs, 120)
+	t.Len(cccs, 15)
+	t.Len(moreBbbs, 2)
+
+	var wrong bbb
+	t.Equal(ErrNotSlice, t.access.BatchGet().Add(&wrong, "id", bIds[0]).Run())
+}
+
 func (t *AccessSuite) TestCreateRelationship() {
 
 	a := aaa{
@@ -732,6 +764,329 @@ func (t *AccessSuite) TestOrder() {
 	t.Equal(expected, eees)
 }
 
+func (t *AccessSuite) TestQueryDynamodbavIndex() {
+	candidates := []*iii{
+		{Ia: "X", Ib: 1},
+		{Ia: "X", Ib: 2},
+		{Ia: "Y", Ib: 3},
+	}
+
+	for _, candidate := range candidates {
+		t.Nil(t.access.Create(candidate))
+	}
+
+	expr, err := expression.NewBuilder().
+		WithKeyCondition(expression.Key("iia").Equal(expression.Value("X"))).
+		Build()
+	t.Nil(err)
+
+	items := []iii{}
+	t.Nil(t.access.Query(&items, RequestInput{
+		Expr:             expr,
+		IndexName:        "index",
+		ScanIndexForward: true,
+	}))
+
+	t.Equal([]iii{*candidates[0], *candidates[1]}, items)
+}
+
+func (t *AccessSuite) TestCreateWithModelConfig() {
+	k := &kkk{Key: "key", Value: "value"}
+	t.Nil(t.access.Create(k))
+
+	created, err := time.Parse(time.RFC3339, k.CreatedAt)
+	t.Nil(err)
+	t.WithinDuration(time.Now(), created, time.Minute)
+	t.Equal(k.CreatedAt, k.UpdatedAt)
+
+	item := kkk{}
+	t.Nil(t.access.GetItem(&item, "key", "key"))
+	t.Equal(*k, item)
+
+	t.Equal(ErrNoSoftDelete, t.access.SoftDelete(&item, "key", "key"))
+}
+
+func (t *AccessSuite) TestScanWithModelConfig() {
+	removed, kept := &mmm{Ma: "Ma"}, &mmm{Ma: "Ma"}
+	t.Nil(t.access.Create(removed))
+	t.Nil(t.access.Create(kept))
+	t.Nil(t.access.SoftDelete(&mmm{}, "id", removed.Id))
+
+	items := []mmm{}
+	_, err := t.access.ScanByFilter(&items, expression.Name("mma").Equal(expression.Value("Ma")))
+	t.Nil(err)
+	t.Len(items, 1)
+	t.Equal(kept.Id, items[0].Id)
+
+	k := &kkk{Key: "key", Value: "value"}
+	t.Nil(t.access.Create(k))
+
+	keys := []kkk{}
+	_, err = t.access.ScanByFilter(&keys, expression.Name("value").Equal(expression.Value("value")))
+	t.Nil(err)
+	t.Equal([]kkk{*k}, keys)
+}
+
+func (t *AccessSuite) TestGetItemWithoutModel() {
+	u := &user{FirstName: "John", Email: "john@gmail.com"}
+	t.Nil(t.access.Create(u))
+
+	item := user{}
+	t.Nil(t.access.GetItem(&item, "email", "john@gmail.com"))
+	t.Equal(*u, item)
+
+	items := []user{}
+	_, err := t.access.ScanByAttribute(&items, "first_name", "John")
+	t.Nil(err)
+	t.Len(items, 1)
+}
+
+func (t *AccessSuite) TestCreateWithIDGenerator() {
+	t.access.idGenerator = &SequenceGenerator{Prefix: "ccc_"}
+	defer func() {
+		t.access.idGenerator = UUIDGenerator{}
+	}()
+
+	c := &ccc{Ca: "Ca"}
+	t.Nil(t.access.Create(c))
+	t.Equal("ccc_00000000000000000001", c.Id)
+
+	item := ccc{}
+	t.Nil(t.access.GetItem(&item, "id", "ccc_00000000000000000001"))
+	t.Equal(*c, item)
+}
+
+func (t *AccessSuite) TestTimestampsByClock() {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t.access.clock = godynamotest.NewSteppingClock(start, time.Hour)
+	defer func() {
+		t.access.clock = SystemClock{}
+	}()
+
+	a := &aaa{Aa: "Aa"}
+	t.Nil(t.access.Create(a))
+	t.Equal(start.Unix(), a.Created)
+	t.Equal(start.Unix(), a.Updated)
+
+	t.Nil(t.access.Update(a))
+	t.Equal(start.Add(time.Hour).Unix(), a.Updated)
+
+	t.Nil(t.access.SoftDelete(a, "id", a.Id))
+	t.Equal(start.Add(2*time.Hour).Unix(), a.Deleted)
+}
+
+func (t *AccessSuite) TestHooks() {
+	l := &lll{La: "  La  "}
+	t.Nil(t.access.Create(l))
+	t.Equal("La", l.La)
+	t.Equal([]string{"before_create", "validate", "after_create"}, l.Hooks)
+
+	l.Hooks = nil
+	t.Nil(t.access.Update(l))
+	t.Equal([]string{"before_update", "validate"}, l.Hooks)
+
+	loaded := &lll{}
+	t.Nil(t.access.GetItem(loaded, "id", l.Id))
+	t.Equal("La", loaded.La)
+	t.Equal([]string{"after_load"}, loaded.Hooks)
+
+	loadedAll := &[]lll{}
+	t.Nil(t.access.GetItems(loadedAll, "id", []string{l.Id}))
+	t.Len(*loadedAll, 1)
+	t.Equal([]string{"after_load"}, (*loadedAll)[0].Hooks)
+
+	deleted := &lll{}
+	t.Nil(t.access.SoftDelete(deleted, "id", l.Id))
+	t.Equal([]string{"after_load", "before_delete", "validate"}, deleted.Hooks)
+}
+
+func (t *AccessSuite) TestHooksAbort() {
+	t.Equal(errEmptyLa, t.access.Create(&lll{La: "   "}))
+
+	all := &[]lll{}
+	_, err := t.access.Scan(all, RequestInput{})
+	t.Nil(err)
+	t.Len(*all, 0)
+}
+
+func (t *AccessSuite) TestInterceptors() {
+	var names []OperationName
+	t.access.interceptors = []Interceptor{func(op *Operation, next Handler) error {
+		names = append(names, op.Name)
+		return next(op)
+	}}
+	defer func() {
+		t.access.interceptors = nil
+	}()
+
+	a := &aaa{Aa: "Aa"}
+	t.Nil(t.access.Create(a))
+	t.Nil(t.access.SoftDelete(a, "id", a.Id))
+	t.Equal([]OperationName{OperationCreate, OperationSoftDelete, OperationGetItem}, names)
+}
+
+func (t *AccessSuite) TestConsumedCapacity() {
+	collector := NewCapacityCollector()
+	access := t.access.Track(collector)
+
+	a := &aaa{Aa: "Aa"}
+	t.Nil(access.Create(a))
+	t.Nil(access.GetItem(&aaa{}, "id", a.Id))
+
+	usage := collector.Usage()
+	t.Len(usage, 2)
+	t.Equal(CapacityKey{OperationCreate, "access_aaa", ""}, usage[0].CapacityKey)
+	t.Equal(CapacityKey{OperationGetItem, "access_aaa", ""}, usage[1].CapacityKey)
+	t.True(collector.Total() > 0)
+}
+
+func (t *AccessSuite) TestTableNotFound() {
+	access := *t.access
+	access.tablePrefix = "missing_"
+
+	err := access.GetItem(&aaa{}, "id", "1")
+	t.True(errors.Is(err, ErrTableNotFound))
+
+	var awsErr awserr.Error
+	t.True(errors.As(err, &awsErr))
+	t.Equal(dynamodb.ErrCodeResourceNotFoundException, awsErr.Code())
+}
+
+func (t *AccessSuite) TestCache() {
+	t.access.cache = NewCache(100, CachePolicy{TTL: time.Minute, NotFoundTTL: time.Minute})
+	defer func() {
+		t.access.cache = nil
+	}()
+
+	t.Equal(ErrNotFound, t.access.GetItem(&aaa{}, "id", "cached"))
+	t.Equal(1, t.access.cache.Len())
+
+	a := &aaa{Model: Model{Id: "cached"}, Aa: "Aa"}
+	t.Nil(t.access.Create(a))
+	t.Equal(0, t.access.cache.Len())
+
+	found := &aaa{}
+	t.Nil(t.access.GetItem(found, "id", "cached"))
+	t.Equal("Aa", found.Aa)
+
+	a.Aa = "Ab"
+	t.Nil(t.access.Update(a))
+	t.Nil(t.access.GetItem(found, "id", "cached"))
+	t.Equal("Ab", found.Aa)
+
+	t.Nil(t.access.Delete(&aaa{}, "id", "cached"))
+	t.Equal(ErrNotFound, t.access.GetItem(&aaa{}, "id", "cached"))
+}
+
+func (t *AccessSuite) TestQueryBuilder() {
+	candidates := []*user{
+		{FirstName: "Jane", LastName: "Doe", Email: "jane@gmail.com", CreatedAt: 1},
+		{FirstName: "John", LastName: "Doe", Email: "john@gmail.com", CreatedAt: 1},
+		{FirstName: "Jack", LastName: "Roe", Email: "jack@gmail.com", CreatedAt: 1},
+		{FirstName: "Paul", LastName: "Doe", Email: "paul@gmail.com", CreatedAt: 1},
+	}
+
+	for _, candidate := range candidates {
+		t.Nil(t.access.Create(candidate))
+	}
+
+	users := []user{}
+	t.Nil(t.access.From(&users).
+		Index("created_at_first_name_index").
+		Where("created_at", Eq, 1).
+		And("first_name", BeginsWith, "J").
+		Filter("last_name", Eq, "Doe").
+		Desc().
+		Limit(20).
+		All())
+	t.Equal([]user{*candidates[1], *candidates[0]}, users)
+
+	one := &user{}
+	t.Nil(t.access.From(one).Where("email", Eq, "paul@gmail.com").All())
+	t.Equal(candidates[3], one)
+}
+
+func (t *AccessSuite) TestQuerySelectsIndex() {
+	for _, da := range []string{"John", "John", "James"} {
+		t.Nil(t.access.Create(&ddd{Da: da}))
+	}
+
+	var indexes []string
+	t.access.interceptors = []Interceptor{func(op *Operation, next Handler) error {
+		indexes = append(indexes, op.Input.IndexName)
+		return next(op)
+	}}
+	defer func() {
+		t.access.interceptors = nil
+	}()
+
+	expr, err := expression.NewBuilder().
+		WithKeyCondition(expression.Key("dda").Equal(expression.Value("John"))).
+		Build()
+	t.Nil(err)
+
+	ddds := []ddd{}
+	index, err := t.access.QueryIndex(&ddds, RequestInput{Expr: expr})
+	t.Nil(err)
+	t.Equal("index", index)
+	t.Len(ddds, 2)
+	t.Equal([]string{"index"}, indexes)
+
+	expr, err = expression.NewBuilder().
+		WithKeyCondition(expression.Key("ddb").Equal(expression.Value("John"))).
+		Build()
+	t.Nil(err)
+
+	t.True(errors.Is(t.access.Query(&ddds, RequestInput{Expr: expr}), ErrNoMatchingIndex))
+}
+
+func (t *AccessSuite) TestScanPolicy() {
+	t.Nil(t.access.Create(&aaa{Aa: "scanned"}))
+
+	var reports []ScanReport
+	access := *t.access
+	WithScanPolicy(ScanPolicy{Mode: ScanForbid, Report: func(report ScanReport) {
+		reports = append(reports, report)
+	}})(&access)
+
+	_, err := access.ScanByAttribute(&[]aaa{}, "aaa", "scanned")
+	t.True(errors.Is(err, ErrScanForbidden))
+
+	items := []aaa{}
+	_, err = access.AllowScan().ScanByAttribute(&items, "aaa", "scanned")
+	t.Nil(err)
+	t.Len(items, 1)
+
+	t.Len(reports, 2)
+	t.Contains(reports[0].Caller, "access_test.go:")
+	t.True(reports[1].OptedIn)
+}
+
+func (t *AccessSuite) TestFilterExpired() {
+	live := &ggg{Ga: "live"}
+	t.Nil(t.access.ExpiresIn(live, time.Hour))
+	t.Nil(t.access.Create(live))
+
+	expired := &ggg{Ga: "expired"}
+	t.Nil(t.access.ExpiresIn(expired, -time.Hour))
+	t.Nil(t.access.Create(expired))
+
+	t.access.filterExpired = true
+	defer func() {
+		t.access.filterExpired = false
+	}()
+
+	item := ggg{}
+	t.Equal(ErrNotFound, t.access.GetItem(&item, "id", expired.Id))
+	t.Nil(t.access.GetItem(&item, "id", live.Id))
+
+	items := []ggg{}
+	_, err := t.access.ScanByFilter(&items, expression.Name("gga").AttributeExists())
+	t.Nil(err)
+	t.Len(items, 1)
+	t.Equal(live.Id, items[0].Id)
+}
+
 func TestAccessSuite(t *testing.T) {
 	suite.Run(t, &AccessSuite{})
 }
@@ -0,0 +1,200 @@
+package godynamo
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// attributeName, name of attribute under which dynamodbattribute marshals given field,
+// dynamodbav tag has precedence over json tag, name of field is used when tags do not name it,
+// returns false when the field is not marshaled at all
+func attributeName(field reflect.StructField) (string, bool) {
+	// unexported fields are skipped, unless they are embedded
+	if field.PkgPath != "" && !field.Anonymous {
+		return "", false
+	}
+
+	tag, ok := field.Tag.Lookup("dynamodbav")
+	if !ok {
+		tag = field.Tag.Get("json")
+	}
+
+	if tag == "-" {
+		return "", false
+	}
+
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name, true
+	}
+
+	return field.Name, true
+}
+
+// modelField, field of model including fields promoted from embedded structs
+type modelField struct {
+	reflect.StructField
+
+	// path, names of embedded structs and name of the field, like Model.Id
+	path string
+
+	// index, index sequence for reflect.Value.FieldByIndex
+	index []int
+
+	// attribute, name of attribute under which is the field marshaled
+	attribute string
+
+	depth int
+}
+
+// modelFields, fields of model in order of declaration, fields of anonymous embedded structs
+// are flattened the same way as dynamodbattribute does, shallower field hides deeper one with
+// the same attribute, attributes declared by several fields on the same level are reported as problems
+func modelFields(t reflect.Type) ([]modelField, []SchemaProblem) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	var all []modelField
+	var problems []SchemaProblem
+	collectFields(t, nil, "", 0, map[reflect.Type]bool{t: true}, &all, &problems)
+
+	byAttribute := map[string][]modelField{}
+	for _, field := range all {
+		byAttribute[field.attribute] = append(byAttribute[field.attribute], field)
+	}
+
+	var fields []modelField
+	for _, field := range all {
+		dominant := dominantFields(byAttribute[field.attribute])
+		_, tagged := field.Tag.Lookup("godynamo")
+
+		if field.depth != dominant[0].depth {
+			if tagged {
+				problems = append(problems, SchemaProblem{
+					Field:   field.path,
+					Tag:     field.Tag.Get("godynamo"),
+					Message: fmt.Sprintf("field is hidden by field %s with the same attribute %q", dominant[0].path, field.attribute),
+				})
+			}
+			continue
+		}
+
+		if len(dominant) > 1 {
+			if field.path == dominant[0].path {
+				paths := make([]string, 0, len(dominant))
+				for _, d := range dominant {
+					paths = append(paths, d.path)
+				}
+				problems = append(problems, SchemaProblem{
+					Message: fmt.Sprintf("attribute %q is declared by fields %s on the same level of embedding", field.attribute, strings.Join(paths, ", ")),
+				})
+			}
+			continue
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, problems
+}
+
+func collectFields(t reflect.Type, index []int, prefix string, depth int, visiting map[reflect.Type]bool, fields *[]modelField, problems *[]SchemaProblem) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if embeddedType, ok := embeddedStruct(field); ok {
+			// protection against structs embedding each other through pointers
+			if visiting[embeddedType] {
+				continue
+			}
+
+			visiting[embeddedType] = true
+			collectFields(embeddedType, fieldIndex, prefix+field.Name+".", depth+1, visiting, fields, problems)
+			delete(visiting, embeddedType)
+			continue
+		}
+
+		name, ok := attributeName(field)
+		if !ok {
+			if dynamoTag, tagged := field.Tag.Lookup("godynamo"); tagged {
+				*problems = append(*problems, SchemaProblem{
+					Field:   prefix + field.Name,
+					Tag:     dynamoTag,
+					Message: "field is not marshaled, it is unexported or its tag is \"-\"",
+				})
+			}
+			continue
+		}
+
+		*fields = append(*fields, modelField{
+			StructField: field,
+			path:        prefix + field.Name,
+			index:       fieldIndex,
+			attribute:   name,
+			depth:       depth,
+		})
+	}
+}
+
+// embeddedStruct, returns type of anonymous struct or pointer to struct, whose fields are flattened into model
+func embeddedStruct(field reflect.StructField) (reflect.Type, bool) {
+	if !field.Anonymous {
+		return nil, false
+	}
+
+	tag, ok := field.Tag.Lookup("dynamodbav")
+	if !ok {
+		tag = field.Tag.Get("json")
+	}
+
+	// embedded struct named by tag is marshaled as nested map
+	if tag == "-" || strings.Split(tag, ",")[0] != "" {
+		return nil, false
+	}
+
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	return t, true
+}
+
+// dominantFields, shallowest fields of the same attribute
+func dominantFields(fields []modelField) []modelField {
+	var dominant []modelField
+	for _, field := range fields {
+		if len(dominant) == 0 || field.depth < dominant[0].depth {
+			dominant = []modelField{field}
+		} else if field.depth == dominant[0].depth {
+			dominant = append(dominant, field)
+		}
+	}
+
+	return dominant
+}
+
+// fieldByIndex, like reflect.Value.FieldByIndex, but allocates nil embedded pointers on the way
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v
+}
@@ -0,0 +1,126 @@
+package godynamo
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws/defaults"
+	"github.com/stretchr/testify/suite"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type TenantModel struct {
+	TenantId string `json:"tenant_id" godynamo:"hash"`
+}
+
+type Audited struct {
+	AuditedBy string `json:"audited_by" godynamo:"global_secondary_index(audit_index:hash)"`
+	AuditedAt int64  `json:"audited_at" godynamo:"global_secondary_index(audit_index:range)"`
+}
+
+type Expiring struct {
+	Expires int64 `json:"expires,omitempty" godynamo:"ttl"`
+}
+
+type tenantItem struct {
+	TenantModel
+	*Audited
+	*Expiring
+
+	Name string `json:"name" godynamo:"range"`
+}
+
+type Node struct {
+	*Edge
+
+	Name string `json:"name" godynamo:"hash"`
+}
+
+type Edge struct {
+	*Node
+
+	To string `json:"to"`
+}
+
+type hiddenHash struct {
+	Model
+
+	Id string `json:"id"`
+}
+
+type ambiguousHash struct {
+	TenantModel
+	OtherTenant
+}
+
+type OtherTenant struct {
+	TenantId string `dynamodbav:"tenant_id"`
+}
+
+type AttributeSuite struct {
+	suite.Suite
+
+	access *DynamoAccess
+}
+
+func (t *AttributeSuite) SetupSuite() {
+	t.access = NewDynamoAccess(defaults.Config(), "attribute_")
+}
+
+func (t *AttributeSuite) TestEmbeddedStructs() {
+	t.Nil(ValidateModel(&tenantItem{}))
+
+	schema, err := t.access.Schema(&tenantItem{})
+	t.Nil(err)
+
+	t.Equal(KeySchema{Hash: "tenant_id", Range: "name"}, schema.Key)
+	t.Len(schema.GlobalIndexes, 1)
+	t.Equal(KeySchema{Hash: "audited_by", Range: "audited_at"}, schema.GlobalIndexes[0].Key)
+	t.Equal("expires", schema.TTL)
+}
+
+func (t *AttributeSuite) TestEmbeddedPointerIsAllocated() {
+	at := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	item := &tenantItem{}
+	t.Nil(t.access.ExpiresAt(item, at))
+	t.Equal(at.Unix(), item.Expires)
+}
+
+func (t *AttributeSuite) TestEmbeddingCycle() {
+	fields, problems := modelFields(reflect.TypeOf(&Node{}))
+	t.Len(problems, 0)
+
+	paths := []string{}
+	for _, field := range fields {
+		paths = append(paths, field.path)
+	}
+	t.Equal([]string{"Edge.To", "Name"}, paths)
+}
+
+func (t *AttributeSuite) TestHiddenField() {
+	err := ValidateModel(&hiddenHash{})
+
+	t.Equal(&SchemaError{
+		Model: "hiddenHash",
+		Problems: []SchemaProblem{
+			{Field: "Model.Id", Tag: "hash", Message: "field is hidden by field Id with the same attribute \"id\""},
+			{Message: "missing hash key, tag one field as godynamo:\"hash\""},
+		},
+	}, err)
+}
+
+func (t *AttributeSuite) TestAmbiguousField() {
+	err := ValidateModel(&ambiguousHash{})
+
+	t.Equal(&SchemaError{
+		Model: "ambiguousHash",
+		Problems: []SchemaProblem{
+			{Message: "attribute \"tenant_id\" is declared by fields TenantModel.TenantId, OtherTenant.TenantId on the same level of embedding"},
+			{Message: "missing hash key, tag one field as godynamo:\"hash\""},
+		},
+	}, err)
+}
+
+func TestAttributeSuite(t *testing.T) {
+	suite.Run(t, &AttributeSuite{})
+}
@@ -0,0 +1,253 @@
+package godynamo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// maximal number of keys of one BatchGetItem request
+const batchGetLimit = 100
+
+// BatchGet, collects keys of items of several models, which are read by combined BatchGetItem requests
+// of at most 100 keys, unprocessed keys are retried by retry policy, or DefaultRetryPolicy when none is set,
+// found items are unmarshaled into slices of Add
+type BatchGet struct {
+	access  *DynamoAccess
+	entries []*batchGetEntry
+	err     error
+}
+
+// batchGetEntry, keys of items read into one slice
+type batchGetEntry struct {
+	op    *Operation
+	items []map[string]dynamodb.AttributeValue
+}
+
+// batchGetKey, key of item of the table
+type batchGetKey struct {
+	table string
+	key   map[string]dynamodb.AttributeValue
+}
+
+// BatchGet, starts batch get of items of several models
+func (a *DynamoAccess) BatchGet() *BatchGet {
+	return &BatchGet{access: a}
+}
+
+// Add, items of given string values of key attribute are read into slice item, e.g. Add(&bbbs, "id", ids...)
+func (b *BatchGet) Add(item interface{}, key string, values ...string) *BatchGet {
+	keys := make([]map[string]dynamodb.AttributeValue, 0, len(values))
+	for _, value := range values {
+		keys = append(keys, map[string]dynamodb.AttributeValue{
+			key: {S: aws.String(value)},
+		})
+	}
+
+	return b.AddKeys(item, keys...)
+}
+
+// AddKeys, items of given primary keys are read into slice item, e.g. keys with hash and range attributes,
+// every key must have exactly the hash and range attributes of the model
+func (b *BatchGet) AddKeys(item interface{}, keys ...map[string]dynamodb.AttributeValue) *BatchGet {
+	if b.err != nil {
+		return b
+	}
+
+	op, slice, err := b.access.operation(OperationGetItems, item)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	if !slice {
+		b.err = ErrNotSlice
+		return b
+	}
+
+	attributes := primaryKeyAttributes(op.Model)
+	for _, key := range keys {
+		if len(primaryKey(op.Model, key)) != len(attributes) || len(key) != len(attributes) {
+			names := make([]string, 0, len(key))
+			for name := range key {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			b.err = fmt.Errorf("%w of model %s: key (%s), primary key (%s)", ErrInvalidKey, op.Model.Name(),
+				strings.Join(names, ", "), strings.Join(attributes, ", "))
+			return b
+		}
+	}
+
+	op.Keys = keys
+	b.entries = append(b.entries, &batchGetEntry{op: op})
+	return b
+}
+
+// Keys, keys of all items by their tables, e.g. for interceptors
+func (b *BatchGet) Keys() map[string][]map[string]dynamodb.AttributeValue {
+	keys := map[string][]map[string]dynamodb.AttributeValue{}
+	for _, entry := range b.entries {
+		keys[entry.op.Table] = append(keys[entry.op.Table], entry.op.Keys...)
+	}
+
+	return keys
+}
+
+// Run, reads items of all keys and unmarshals them into their slices, items which are not found are left out,
+// when keys are left unprocessed after retries, found items are unmarshaled and *UnprocessedKeysError is returned
+func (b *BatchGet) Run() error {
+	if b.err != nil {
+		return b.err
+	}
+
+	return b.access.intercept(&Operation{Name: OperationBatchGet, Item: b}, b.run)
+}
+
+func (b *BatchGet) run(op *Operation) error {
+	a := b.access
+
+	// keys read from db, each only once, in order of Add
+	var keys []batchGetKey
+	requested := map[string]bool{}
+	models := map[string]reflect.Type{}
+	for _, entry := range b.entries {
+		models[entry.op.Table] = entry.op.Model
+		entry.items = nil
+		for _, key := range entry.op.Keys {
+			if found, ok := a.cached(entry.op, key); ok {
+				op.Cached = true
+				if len(found) != 0 {
+					entry.items = append(entry.items, found)
+				}
+				continue
+			}
+
+			if id := cacheKey(entry.op.Table, key); !requested[id] {
+				requested[id] = true
+				keys = append(keys, batchGetKey{table: entry.op.Table, key: key})
+			}
+		}
+	}
+
+	version := a.cacheVersion()
+	fetched := map[string]map[string]dynamodb.AttributeValue{}
+	unprocessed := &UnprocessedKeysError{Keys: map[string][]map[string]dynamodb.AttributeValue{}}
+	for _, reqItems := range batchGetRequests(keys) {
+		err := a.sendBatch(op, func() (int, error) {
+			for table := range reqItems {
+				a.rateLimiter.wait(&Operation{Name: op.Name, Table: table})
+			}
+
+			var result *dynamodb.BatchGetItemOutput
+			if err := a.send(op, func() (err error) {
+				result, err = a.svc.BatchGetItemRequest(&dynamodb.BatchGetItemInput{
+					RequestItems:           reqItems,
+					ReturnConsumedCapacity: a.returnConsumedCapacity(),
+				}).Send()
+				return err
+			}); err != nil {
+				return 0, err
+			}
+			for i := range result.ConsumedCapacity {
+				a.consumed(op, &result.ConsumedCapacity[i])
+			}
+
+			for table, items := range result.Responses {
+				for _, item := range items {
+					fetched[cacheKey(table, primaryKey(models[table], item))] = item
+				}
+			}
+
+			reqItems = result.UnprocessedKeys
+			left := 0
+			for _, keysAndAttributes := range reqItems {
+				left += len(keysAndAttributes.Keys)
+			}
+			return left, nil
+		})
+		// keys left unprocessed do not stop other requests, found items are loaded anyway
+		if errors.Is(err, ErrUnprocessedItems) {
+			for table, keysAndAttributes := range reqItems {
+				unprocessed.Keys[table] = append(unprocessed.Keys[table], keysAndAttributes.Keys...)
+				for _, key := range keysAndAttributes.Keys {
+					requested[cacheKey(table, key)] = false
+				}
+			}
+		} else if err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range b.entries {
+		for _, key := range entry.op.Keys {
+			id := cacheKey(entry.op.Table, key)
+			if !requested[id] {
+				continue
+			}
+
+			item := fetched[id]
+			if a.cache != nil {
+				a.cacheItem(entry.op, key, item, version)
+			}
+			if item != nil {
+				entry.items = append(entry.items, item)
+			}
+		}
+
+		if err := a.loadItems(entry.op, entry.items); err != nil {
+			return err
+		}
+	}
+
+	if len(unprocessed.Keys) > 0 {
+		return unprocessed
+	}
+
+	return nil
+}
+
+// UnprocessedKeysError, keys left unprocessed by batch get or GetItems after retries ran out, by their tables
+type UnprocessedKeysError struct {
+	Keys map[string][]map[string]dynamodb.AttributeValue
+}
+
+// Is, unprocessed keys error is error of unprocessed items
+func (e *UnprocessedKeysError) Is(target error) bool {
+	return target == ErrUnprocessedItems
+}
+
+func (e *UnprocessedKeysError) Error() string {
+	tables := make([]string, 0, len(e.Keys))
+	for table, keys := range e.Keys {
+		tables = append(tables, fmt.Sprintf("%s (%d keys)", table, len(keys)))
+	}
+	sort.Strings(tables)
+
+	return fmt.Sprintf("%s: %s", ErrUnprocessedItems, strings.Join(tables, ", "))
+}
+
+// batchGetRequests, request items of BatchGetItem requests, each of at most 100 keys
+func batchGetRequests(keys []batchGetKey) []map[string]dynamodb.KeysAndAttributes {
+	var requests []map[string]dynamodb.KeysAndAttributes
+	for start := 0; start < len(keys); start += batchGetLimit {
+		end := start + batchGetLimit
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		reqItems := map[string]dynamodb.KeysAndAttributes{}
+		for _, key := range keys[start:end] {
+			keysAndAttributes := reqItems[key.table]
+			keysAndAttributes.Keys = append(keysAndAttributes.Keys, key.key)
+			reqItems[key.table] = keysAndAttributes
+		}
+		requests = append(requests, reqItems)
+	}
+
+	return requests
+}
@@ -0,0 +1,109 @@
+package godynamo
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/defaults"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/suite"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type BatchGetSuite struct {
+	suite.Suite
+
+	access *DynamoAccess
+}
+
+func (t *BatchGetSuite) SetupTest() {
+	t.access = NewDynamoAccess(defaults.Config(), "x_", WithCache(NewCache(10, CachePolicy{TTL: time.Minute, NotFoundTTL: time.Minute})))
+}
+
+func (t *BatchGetSuite) cache(table, id string, item map[string]dynamodb.AttributeValue) {
+	t.access.cache.set(cacheKey(table, map[string]dynamodb.AttributeValue{"id": {S: aws.String(id)}}), item, time.Now().Add(time.Minute))
+}
+
+func (t *BatchGetSuite) TestRunFromCache() {
+	t.cache("x_bbb", "b1", map[string]dynamodb.AttributeValue{"id": {S: aws.String("b1")}, "bba": {S: aws.String("Ba")}})
+	t.cache("x_bbb", "b2", nil)
+	t.cache("x_ccc", "c1", map[string]dynamodb.AttributeValue{"id": {S: aws.String("c1")}, "cca": {S: aws.String("Ca")}})
+
+	var ops []*Operation
+	t.access.interceptors = []Interceptor{func(op *Operation, next Handler) error {
+		ops = append(ops, op)
+		return next(op)
+	}}
+
+	var bbbs []bbb
+	var cccs []*ccc
+	t.Nil(t.access.BatchGet().Add(&bbbs, "id", "b1", "b2").Add(&cccs, "id", "c1").Run())
+
+	t.Equal([]bbb{{Model: Model{Id: "b1"}, Ba: "Ba"}}, bbbs)
+	t.Len(cccs, 1)
+	t.Equal("Ca", cccs[0].Ca)
+
+	t.Len(ops, 1)
+	t.Equal(OperationBatchGet, ops[0].Name)
+	t.Empty(ops[0].Keys)
+	keys := ops[0].Item.(*BatchGet).Keys()
+	t.Len(keys["x_bbb"], 2)
+	t.Len(keys["x_ccc"], 1)
+	t.True(ops[0].Cached)
+}
+
+func (t *BatchGetSuite) TestAddFails() {
+	var wrong bbb
+	t.Equal(ErrNotSlice, t.access.BatchGet().Add(&wrong, "id", "b1").Add(&[]ccc{}, "id", "c1").Run())
+
+	t.Equal(ErrNotPointer, t.access.BatchGet().Add([]ccc{}, "id", "c1").Run())
+
+	err := t.access.BatchGet().Add(&[]ccc{}, "cca", "c1").Run()
+	t.True(errors.Is(err, ErrInvalidKey))
+	t.EqualError(err, "key does not match primary key of model ccc: key (cca), primary key (id)")
+
+	err = t.access.BatchGet().AddKeys(&[]fff{}, map[string]dynamodb.AttributeValue{
+		"id":  {S: aws.String("f1")},
+		"ffb": {S: aws.String("b")},
+		"ffa": {S: aws.String("a")},
+	}).Run()
+	t.EqualError(err, "key does not match primary key of model fff: key (ffa, ffb, id), primary key (id, ffb)")
+
+	err = t.access.BatchGet().AddKeys(&[]fff{}, map[string]dynamodb.AttributeValue{"id": {S: aws.String("f1")}}).Run()
+	t.True(errors.Is(err, ErrInvalidKey))
+}
+
+func (t *BatchGetSuite) TestUnprocessedKeysError() {
+	err := error(&UnprocessedKeysError{Keys: map[string][]map[string]dynamodb.AttributeValue{
+		"x_ccc": {{"id": {S: aws.String("c1")}}},
+		"x_bbb": {{"id": {S: aws.String("b1")}}, {"id": {S: aws.String("b2")}}},
+	}})
+	t.True(errors.Is(err, ErrUnprocessedItems))
+	t.EqualError(err, "items of batch left unprocessed: x_bbb (2 keys), x_ccc (1 keys)")
+}
+
+func (t *BatchGetSuite) TestRequests() {
+	var keys []batchGetKey
+	for i := 0; i < 120; i++ {
+		keys = append(keys, batchGetKey{table: "x_bbb", key: map[string]dynamodb.AttributeValue{"id": {S: aws.String(strconv.Itoa(i))}}})
+	}
+	for i := 0; i < 90; i++ {
+		keys = append(keys, batchGetKey{table: "x_ccc", key: map[string]dynamodb.AttributeValue{"id": {S: aws.String(strconv.Itoa(i))}}})
+	}
+
+	requests := batchGetRequests(keys)
+	t.Len(requests, 3)
+	t.Len(requests[0]["x_bbb"].Keys, 100)
+	t.Len(requests[0], 1)
+	t.Len(requests[1]["x_bbb"].Keys, 20)
+	t.Len(requests[1]["x_ccc"].Keys, 80)
+	t.Len(requests[2]["x_ccc"].Keys, 10)
+	t.Len(requests[2], 1)
+
+	t.Empty(batchGetRequests(nil))
+}
+
+func TestBatchGetSuite(t *testing.T) {
+	suite.Run(t, new(BatchGetSuite))
+}
@@ -0,0 +1,295 @@
+package godynamo
+
+import (
+	"container/list"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachePolicy, how long are items of model kept in cache, zero TTL disables caching,
+// zero NotFoundTTL disables caching of items which were not found
+type CachePolicy struct {
+	TTL         time.Duration
+	NotFoundTTL time.Duration
+}
+
+// CachePolicyProvider, model with its own cache policy, gets the default policy of the cache
+type CachePolicyProvider interface {
+	CachePolicy(defaults CachePolicy) CachePolicy
+}
+
+// Cache, lru cache of items read by GetItem and GetItems, keyed by table and primary key,
+// items are invalidated by writes of the same DynamoAccess, safe for concurrent use
+type Cache struct {
+	mutex    sync.Mutex
+	size     int
+	defaults CachePolicy
+	entries  map[string]*list.Element
+	lru      *list.List
+
+	// version, counter of invalidations, items read before invalidation of their key are not cached
+	version     uint64
+	invalidated map[string]uint64
+	// floor, items read before it are not cached, invalidations older than it are forgotten
+	floor uint64
+}
+
+type cacheEntry struct {
+	key     string
+	item    map[string]dynamodb.AttributeValue
+	expires time.Time
+}
+
+// NewCache, cache of given number of items, with default policy of all models
+func NewCache(size int, defaults CachePolicy) *Cache {
+	return &Cache{
+		size:        size,
+		defaults:    defaults,
+		entries:     map[string]*list.Element{},
+		lru:         list.New(),
+		invalidated: map[string]uint64{},
+	}
+}
+
+// WithCache, GetItem and GetItems read through given cache
+func WithCache(cache *Cache) Option {
+	return func(a *DynamoAccess) {
+		a.cache = cache
+	}
+}
+
+// Len, number of cached items
+func (c *Cache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.lru.Len()
+}
+
+// Purge, removes all items
+func (c *Cache) Purge() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries = map[string]*list.Element{}
+	c.lru.Init()
+	c.forget()
+}
+
+// current, version to read items at, items are cached by fill of the version
+func (c *Cache) current() uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.version
+}
+
+// forget, drops invalidations, items read before are not cached anymore
+func (c *Cache) forget() {
+	c.version++
+	c.floor = c.version
+	c.invalidated = map[string]uint64{}
+}
+
+// get, cached item, nil item of hit means item was not found in db
+func (c *Cache) get(key string, timeNow time.Time) (map[string]dynamodb.AttributeValue, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if !timeNow.Before(entry.expires) {
+		c.lru.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(element)
+	return entry.item, true
+}
+
+// fill, stores item read at version, unless its key was invalidated since, e.g. by concurrent write
+func (c *Cache) fill(key string, item map[string]dynamodb.AttributeValue, expires time.Time, version uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if version < c.floor || c.invalidated[key] > version {
+		return
+	}
+
+	c.store(key, item, expires)
+}
+
+func (c *Cache) set(key string, item map[string]dynamodb.AttributeValue, expires time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.store(key, item, expires)
+}
+
+func (c *Cache) store(key string, item map[string]dynamodb.AttributeValue, expires time.Time) {
+	if element, ok := c.entries[key]; ok {
+		element.Value = &cacheEntry{key: key, item: item, expires: expires}
+		c.lru.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, item: item, expires: expires})
+
+	for c.size > 0 && c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *Cache) remove(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.lru.Remove(element)
+		delete(c.entries, key)
+	}
+
+	// invalidations are kept for as many keys as items
+	if c.size > 0 && len(c.invalidated) >= c.size {
+		c.forget()
+	}
+	c.version++
+	c.invalidated[key] = c.version
+}
+
+// cachePolicy, policy of the model, nothing is cached without cache, or of model without hash and range tags,
+// which items can not be keyed by primary key
+func (a *DynamoAccess) cachePolicy(item interface{}) CachePolicy {
+	if a.cache == nil || len(primaryKeyAttributes(modelType(item))) == 0 {
+		return CachePolicy{}
+	}
+
+	policy := a.cache.defaults
+	if provider, ok := item.(CachePolicyProvider); ok {
+		policy = provider.CachePolicy(policy)
+	} else if provider, ok := reflect.New(modelType(item)).Interface().(CachePolicyProvider); ok {
+		policy = provider.CachePolicy(policy)
+	}
+
+	return policy
+}
+
+// cacheVersion, version of cache before items are read from db, see cacheItem
+func (a *DynamoAccess) cacheVersion() uint64 {
+	if a.cache == nil {
+		return 0
+	}
+
+	return a.cache.current()
+}
+
+// cached, item of the key from cache
+func (a *DynamoAccess) cached(op *Operation, key map[string]dynamodb.AttributeValue) (map[string]dynamodb.AttributeValue, bool) {
+	if a.cache == nil || len(primaryKeyAttributes(modelType(op.Item))) == 0 {
+		return nil, false
+	}
+
+	return a.cache.get(cacheKey(op.Table, key), now(a.clock))
+}
+
+// cacheItem, stores item of the key read at version, nil item is stored as not found,
+// item is dropped when the key was invalidated after the version
+func (a *DynamoAccess) cacheItem(op *Operation, key, item map[string]dynamodb.AttributeValue, version uint64) {
+	policy := a.cachePolicy(op.Item)
+
+	ttl := policy.TTL
+	if len(item) == 0 {
+		item, ttl = nil, policy.NotFoundTTL
+	}
+
+	if ttl <= 0 {
+		return
+	}
+
+	a.cache.fill(cacheKey(op.Table, key), item, now(a.clock).Add(ttl), version)
+}
+
+// uncache, invalidates cached item written by operation
+func (a *DynamoAccess) uncache(op *Operation, av map[string]dynamodb.AttributeValue) {
+	if a.cache == nil {
+		return
+	}
+
+	key := op.Key
+	if key == nil {
+		key = primaryKey(modelType(op.Item), av)
+	}
+	// items of model without primary key tags are not cached
+	if len(key) == 0 {
+		return
+	}
+
+	a.cache.remove(cacheKey(op.Table, key))
+}
+
+// primaryKey, hash and range attributes of the item
+func primaryKey(t reflect.Type, av map[string]dynamodb.AttributeValue) map[string]dynamodb.AttributeValue {
+	key := map[string]dynamodb.AttributeValue{}
+
+	for _, attribute := range primaryKeyAttributes(t) {
+		if value, ok := av[attribute]; ok {
+			key[attribute] = value
+		}
+	}
+
+	return key
+}
+
+// primaryKeyAttributes, names of attributes tagged as hash and range
+func primaryKeyAttributes(t reflect.Type) []string {
+	var attributes []string
+	if t == nil {
+		return attributes
+	}
+
+	fields, _ := modelFields(t)
+	for _, field := range fields {
+		dynamoTag, ok := field.Tag.Lookup("godynamo")
+		if ok && (hasTagFunc(dynamoTag, "hash") || hasTagFunc(dynamoTag, "range")) {
+			attributes = append(attributes, field.attribute)
+		}
+	}
+
+	return attributes
+}
+
+// cacheKey, table and sorted attributes of the key
+func cacheKey(table string, key map[string]dynamodb.AttributeValue) string {
+	names := make([]string, 0, len(key))
+	for name := range key {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := []string{table}
+	for _, name := range names {
+		value := key[name]
+		switch {
+		case value.S != nil:
+			parts = append(parts, name+"=S:"+aws.StringValue(value.S))
+		case value.N != nil:
+			parts = append(parts, name+"=N:"+aws.StringValue(value.N))
+		default:
+			parts = append(parts, name+"=B:"+string(value.B))
+		}
+	}
+
+	return strings.Join(parts, "\x00")
+}
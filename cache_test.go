@@ -0,0 +1,199 @@
+package godynamo
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/defaults"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/flowup-labs/godynamo/godynamotest"
+	"github.com/stretchr/testify/suite"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type CacheSuite struct {
+	suite.Suite
+
+	clock  *godynamotest.Clock
+	cache  *Cache
+	access *DynamoAccess
+}
+
+type cachedModel struct {
+	Model
+}
+
+func (cachedModel) CachePolicy(defaults CachePolicy) CachePolicy {
+	defaults.TTL = time.Hour
+	return defaults
+}
+
+// untaggedModel, model of table provisioned outside, without hash and range tags
+type untaggedModel struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (t *CacheSuite) SetupTest() {
+	t.clock = godynamotest.NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	t.cache = NewCache(2, CachePolicy{TTL: time.Minute, NotFoundTTL: time.Second})
+	t.access = NewDynamoAccess(defaults.Config(), "x_", WithCache(t.cache), WithClock(t.clock))
+}
+
+func key(id string) map[string]dynamodb.AttributeValue {
+	return map[string]dynamodb.AttributeValue{"id": {S: aws.String(id)}}
+}
+
+func (t *CacheSuite) TestLRU() {
+	expires := t.clock.Now().Add(time.Minute)
+	t.cache.set("a", key("a"), expires)
+	t.cache.set("b", key("b"), expires)
+
+	_, ok := t.cache.get("a", t.clock.Now())
+	t.True(ok)
+
+	t.cache.set("c", key("c"), expires)
+	t.Equal(2, t.cache.Len())
+
+	_, ok = t.cache.get("b", t.clock.Now())
+	t.False(ok)
+	_, ok = t.cache.get("a", t.clock.Now())
+	t.True(ok)
+
+	_, ok = t.cache.get("c", expires)
+	t.False(ok)
+	t.Equal(1, t.cache.Len())
+
+	t.cache.Purge()
+	t.Equal(0, t.cache.Len())
+}
+
+func (t *CacheSuite) TestGetItemFromCache() {
+	op := &Operation{Table: "x_aaa", Item: &aaa{}}
+	t.access.cacheItem(op, key("1"), map[string]dynamodb.AttributeValue{
+		"id":  {S: aws.String("1")},
+		"aaa": {S: aws.String("Aa")},
+	}, t.access.cacheVersion())
+	t.access.cacheItem(op, key("2"), nil, t.access.cacheVersion())
+
+	item := &aaa{}
+	t.Nil(t.access.GetItem(item, "id", "1"))
+	t.Equal("Aa", item.Aa)
+
+	t.Equal(ErrNotFound, t.access.GetItem(&aaa{}, "id", "2"))
+
+	items := &[]aaa{}
+	t.Nil(t.access.GetItems(items, "id", []string{"1", "2"}))
+	t.Len(*items, 1)
+
+	// not found is cached for a second only
+	t.clock.Advance(time.Second)
+	_, ok := t.access.cached(op, key("2"))
+	t.False(ok)
+
+	t.clock.Advance(time.Minute)
+	_, ok = t.access.cached(op, key("1"))
+	t.False(ok)
+}
+
+func (t *CacheSuite) TestCachePolicy() {
+	t.Equal(CachePolicy{TTL: time.Minute, NotFoundTTL: time.Second}, t.access.cachePolicy(&aaa{}))
+	t.Equal(CachePolicy{TTL: time.Hour, NotFoundTTL: time.Second}, t.access.cachePolicy(&cachedModel{}))
+	t.Equal(CachePolicy{TTL: time.Hour, NotFoundTTL: time.Second}, t.access.cachePolicy(&[]cachedModel{}))
+	t.Equal(CachePolicy{}, NewDynamoAccess(defaults.Config(), "x_").cachePolicy(&aaa{}))
+}
+
+func (t *CacheSuite) TestUncache() {
+	op := &Operation{Table: "x_aaa", Item: &aaa{}}
+	t.access.cacheItem(op, key("1"), key("1"), t.access.cacheVersion())
+	t.access.cacheItem(op, key("2"), key("2"), t.access.cacheVersion())
+
+	t.access.uncache(op, map[string]dynamodb.AttributeValue{
+		"id":  {S: aws.String("1")},
+		"aaa": {S: aws.String("Aa")},
+	})
+	_, ok := t.access.cached(op, key("1"))
+	t.False(ok)
+
+	t.access.uncache(&Operation{Table: "x_aaa", Item: &aaa{}, Key: key("2")}, nil)
+	_, ok = t.access.cached(op, key("2"))
+	t.False(ok)
+}
+
+func (t *CacheSuite) TestStaleFill() {
+	op := &Operation{Table: "x_aaa", Item: &aaa{}}
+
+	// item read before concurrent write of its key is not cached
+	version := t.access.cacheVersion()
+	t.access.uncache(&Operation{Table: "x_aaa", Item: &aaa{}, Key: key("1")}, nil)
+	t.access.cacheItem(op, key("1"), key("1"), version)
+	t.access.cacheItem(op, key("2"), key("2"), version)
+	_, ok := t.access.cached(op, key("1"))
+	t.False(ok)
+	_, ok = t.access.cached(op, key("2"))
+	t.True(ok)
+
+	// item read after the write is cached
+	t.access.cacheItem(op, key("1"), key("1"), t.access.cacheVersion())
+	_, ok = t.access.cached(op, key("1"))
+	t.True(ok)
+
+	// items read before purge are not cached
+	version = t.access.cacheVersion()
+	t.cache.Purge()
+	t.access.cacheItem(op, key("3"), key("3"), version)
+	_, ok = t.access.cached(op, key("3"))
+	t.False(ok)
+}
+
+func (t *CacheSuite) TestForgetInvalidations() {
+	cache := NewCache(2, CachePolicy{TTL: time.Minute})
+	expires := time.Now().Add(time.Minute)
+
+	version := cache.current()
+	cache.remove("a")
+	cache.remove("b")
+	cache.remove("c")
+	t.Len(cache.invalidated, 1)
+
+	// invalidations of "a" and "b" are forgotten, so nothing read before is cached
+	cache.fill("a", key("a"), expires, version)
+	cache.fill("d", key("d"), expires, version)
+	t.Equal(0, cache.Len())
+
+	cache.fill("a", key("a"), expires, cache.current())
+	t.Equal(1, cache.Len())
+}
+
+func (t *CacheSuite) TestUntaggedModel() {
+	op := &Operation{Table: "x_untaggedModel", Item: &untaggedModel{}}
+	t.Equal(CachePolicy{}, t.access.cachePolicy(&[]untaggedModel{}))
+
+	t.access.cacheItem(op, key("1"), map[string]dynamodb.AttributeValue{"id": {S: aws.String("1")}}, t.access.cacheVersion())
+	t.access.cacheItem(op, key("2"), nil, t.access.cacheVersion())
+	t.Equal(0, t.cache.Len())
+
+	_, ok := t.access.cached(op, key("1"))
+	t.False(ok)
+
+	t.access.uncache(op, map[string]dynamodb.AttributeValue{"id": {S: aws.String("1")}})
+	t.Empty(t.cache.invalidated)
+}
+
+func (t *CacheSuite) TestCacheKey() {
+	t.Equal(
+		cacheKey("x_ddd", map[string]dynamodb.AttributeValue{"b": {N: aws.String("1")}, "a": {S: aws.String("x")}}),
+		cacheKey("x_ddd", map[string]dynamodb.AttributeValue{"a": {S: aws.String("x")}, "b": {N: aws.String("1")}}),
+	)
+	t.NotEqual(cacheKey("x_aaa", key("1")), cacheKey("x_bbb", key("1")))
+	t.NotEqual(cacheKey("x_aaa", key("1")), cacheKey("x_aaa", map[string]dynamodb.AttributeValue{"id": {N: aws.String("1")}}))
+
+	t.Equal(key("1"), primaryKey(reflect.TypeOf(&aaa{}), map[string]dynamodb.AttributeValue{
+		"id":  {S: aws.String("1")},
+		"aaa": {S: aws.String("Aa")},
+	}))
+}
+
+func TestCacheSuite(t *testing.T) {
+	suite.Run(t, new(CacheSuite))
+}
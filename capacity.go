@@ -0,0 +1,166 @@
+package godynamo
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"sort"
+	"sync"
+)
+
+// CapacityKey, operation, table and index capacity is consumed by, index is empty for the table itself
+type CapacityKey struct {
+	Operation OperationName
+	Table     string
+	Index     string
+}
+
+// CapacityUsage, capacity units consumed on the table or index
+type CapacityUsage struct {
+	CapacityKey
+	Units float64
+}
+
+// CapacityCollector, running totals of consumed capacity, safe for concurrent use
+type CapacityCollector struct {
+	mutex sync.Mutex
+	units map[CapacityKey]float64
+}
+
+func NewCapacityCollector() *CapacityCollector {
+	return &CapacityCollector{units: map[CapacityKey]float64{}}
+}
+
+// Add, adds usage to the totals
+func (c *CapacityCollector) Add(usage ...CapacityUsage) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.units == nil {
+		c.units = map[CapacityKey]float64{}
+	}
+
+	for _, u := range usage {
+		c.units[u.CapacityKey] += u.Units
+	}
+}
+
+// Usage, snapshot of totals, sorted by table, index and operation
+func (c *CapacityCollector) Usage() []CapacityUsage {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	usage := make([]CapacityUsage, 0, len(c.units))
+	for key, units := range c.units {
+		usage = append(usage, CapacityUsage{CapacityKey: key, Units: units})
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Table != usage[j].Table {
+			return usage[i].Table < usage[j].Table
+		}
+		if usage[i].Index != usage[j].Index {
+			return usage[i].Index < usage[j].Index
+		}
+		return usage[i].Operation < usage[j].Operation
+	})
+
+	return usage
+}
+
+// Total, sum of all capacity units consumed
+func (c *CapacityCollector) Total() float64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	total := 0.0
+	for _, units := range c.units {
+		total += units
+	}
+
+	return total
+}
+
+// Reset, clears the totals
+func (c *CapacityCollector) Reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.units = map[CapacityKey]float64{}
+}
+
+// WithConsumedCapacity, every request returns consumed capacity, which is added to the collector
+func WithConsumedCapacity(collector *CapacityCollector) Option {
+	return func(a *DynamoAccess) {
+		a.collectors = append(a.collectors, collector)
+	}
+}
+
+// Track, copy of access, which adds consumed capacity to given collector as well,
+// e.g. to account capacity of one endpoint or request
+func (a *DynamoAccess) Track(collector *CapacityCollector) *DynamoAccess {
+	tracked := *a
+	tracked.collectors = append(append([]*CapacityCollector{}, a.collectors...), collector)
+	return &tracked
+}
+
+// returnConsumedCapacity, capacity requested from db, none when nothing collects or limits it
+func (a *DynamoAccess) returnConsumedCapacity() dynamodb.ReturnConsumedCapacity {
+	if len(a.collectors) == 0 && a.rateLimiter == nil {
+		return ""
+	}
+
+	return dynamodb.ReturnConsumedCapacityIndexes
+}
+
+// consumed, records capacity consumed by operation to the operation and all collectors
+func (a *DynamoAccess) consumed(op *Operation, capacities ...*dynamodb.ConsumedCapacity) {
+	usage := capacityUsage(op.Name, capacities...)
+	if len(usage) == 0 {
+		return
+	}
+
+	op.Capacity = append(op.Capacity, usage...)
+	for _, collector := range a.collectors {
+		collector.Add(usage...)
+	}
+	a.rateLimiter.consume(op, usage)
+}
+
+func capacityUsage(operation OperationName, capacities ...*dynamodb.ConsumedCapacity) []CapacityUsage {
+	var usage []CapacityUsage
+
+	for _, capacity := range capacities {
+		if capacity == nil {
+			continue
+		}
+
+		table := aws.StringValue(capacity.TableName)
+
+		units := aws.Float64Value(capacity.CapacityUnits)
+		if capacity.Table != nil {
+			units = aws.Float64Value(capacity.Table.CapacityUnits)
+		}
+
+		usage = append(usage, CapacityUsage{
+			CapacityKey: CapacityKey{Operation: operation, Table: table},
+			Units:       units,
+		})
+
+		for _, indexes := range []map[string]dynamodb.Capacity{capacity.GlobalSecondaryIndexes, capacity.LocalSecondaryIndexes} {
+			names := make([]string, 0, len(indexes))
+			for name := range indexes {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				usage = append(usage, CapacityUsage{
+					CapacityKey: CapacityKey{Operation: operation, Table: table, Index: name},
+					Units:       aws.Float64Value(indexes[name].CapacityUnits),
+				})
+			}
+		}
+	}
+
+	return usage
+}
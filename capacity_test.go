@@ -0,0 +1,95 @@
+package godynamo
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/defaults"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/suite"
+	"sync"
+	"testing"
+)
+
+type CapacitySuite struct {
+	suite.Suite
+}
+
+func (t *CapacitySuite) TestCapacityUsage() {
+	usage := capacityUsage(OperationQuery, &dynamodb.ConsumedCapacity{
+		CapacityUnits: aws.Float64(3),
+		TableName:     aws.String("x_aaa"),
+		Table:         &dynamodb.Capacity{CapacityUnits: aws.Float64(1)},
+		GlobalSecondaryIndexes: map[string]dynamodb.Capacity{
+			"index2": {CapacityUnits: aws.Float64(1)},
+			"index1": {CapacityUnits: aws.Float64(0.5)},
+		},
+		LocalSecondaryIndexes: map[string]dynamodb.Capacity{
+			"local": {CapacityUnits: aws.Float64(0.5)},
+		},
+	}, nil, &dynamodb.ConsumedCapacity{
+		CapacityUnits: aws.Float64(2),
+		TableName:     aws.String("x_bbb"),
+	})
+
+	t.Equal([]CapacityUsage{
+		{CapacityKey{OperationQuery, "x_aaa", ""}, 1},
+		{CapacityKey{OperationQuery, "x_aaa", "index1"}, 0.5},
+		{CapacityKey{OperationQuery, "x_aaa", "index2"}, 1},
+		{CapacityKey{OperationQuery, "x_aaa", "local"}, 0.5},
+		{CapacityKey{OperationQuery, "x_bbb", ""}, 2},
+	}, usage)
+}
+
+func (t *CapacitySuite) TestCollector() {
+	collector := NewCapacityCollector()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			collector.Add(
+				CapacityUsage{CapacityKey{OperationCreate, "x_aaa", ""}, 1},
+				CapacityUsage{CapacityKey{OperationGetItem, "x_aaa", ""}, 0.5},
+			)
+		}()
+	}
+	wg.Wait()
+
+	t.Equal(15.0, collector.Total())
+	t.Equal([]CapacityUsage{
+		{CapacityKey{OperationCreate, "x_aaa", ""}, 10},
+		{CapacityKey{OperationGetItem, "x_aaa", ""}, 5},
+	}, collector.Usage())
+
+	collector.Reset()
+	t.Equal(0.0, collector.Total())
+	t.Empty(collector.Usage())
+}
+
+func (t *CapacitySuite) TestTrack() {
+	total := NewCapacityCollector()
+	access := NewDynamoAccess(defaults.Config(), "x_")
+	t.Equal(dynamodb.ReturnConsumedCapacity(""), access.returnConsumedCapacity())
+
+	access = NewDynamoAccess(defaults.Config(), "x_", WithConsumedCapacity(total))
+	t.Equal(dynamodb.ReturnConsumedCapacityIndexes, access.returnConsumedCapacity())
+
+	endpoint := NewCapacityCollector()
+	tracked := access.Track(endpoint)
+	t.Len(access.collectors, 1)
+	t.Len(tracked.collectors, 2)
+
+	op := &Operation{Name: OperationScan}
+	tracked.consumed(op, &dynamodb.ConsumedCapacity{
+		CapacityUnits: aws.Float64(4),
+		TableName:     aws.String("x_aaa"),
+	})
+
+	t.Equal([]CapacityUsage{{CapacityKey{OperationScan, "x_aaa", ""}, 4}}, op.Capacity)
+	t.Equal(4.0, total.Total())
+	t.Equal(4.0, endpoint.Total())
+}
+
+func TestCapacitySuite(t *testing.T) {
+	suite.Run(t, new(CapacitySuite))
+}
@@ -0,0 +1,31 @@
+package godynamo
+
+import "time"
+
+// Clock, source of time of all timestamps generated by DynamoAccess
+type Clock interface {
+	Now() time.Time
+}
+
+// WithClock, clock used for generated timestamps, instead of system time
+func WithClock(clock Clock) Option {
+	return func(a *DynamoAccess) {
+		a.clock = clock
+	}
+}
+
+// SystemClock, current system time, default clock
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// now, time of clock, system time when clock is not set
+func now(clock Clock) time.Time {
+	if clock == nil {
+		return time.Now()
+	}
+
+	return clock.Now()
+}
@@ -0,0 +1,138 @@
+package godynamo
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
+	"reflect"
+	"time"
+)
+
+// TimestampFormat, format of generated timestamps
+type TimestampFormat string
+
+const (
+	// TimestampUnix, number of seconds since unix epoch
+	TimestampUnix TimestampFormat = "unix"
+
+	// TimestampUnixMilli, number of milliseconds since unix epoch
+	TimestampUnixMilli TimestampFormat = "unix_milli"
+
+	// TimestampRFC3339, string in RFC3339 format in UTC
+	TimestampRFC3339 TimestampFormat = "rfc3339"
+)
+
+// ModelConfig, attributes which DynamoAccess generates for model,
+// generating of attribute is disabled when its name is empty
+type ModelConfig struct {
+	// IdAttribute, string attribute which gets new id by Create, when it is empty
+	IdAttribute string
+
+	// IDGenerator, generator of new ids, UUIDGenerator is used when nil
+	IDGenerator IDGenerator
+
+	// CreatedAttribute, timestamp set by Create
+	CreatedAttribute string
+
+	// UpdatedAttribute, timestamp set by Create and Update
+	UpdatedAttribute string
+
+	// DeletedAttribute, timestamp set by SoftDelete, items with the timestamp are not found
+	DeletedAttribute string
+
+	TimestampFormat TimestampFormat
+}
+
+// ModelConfigProvider, model which configures its generated attributes,
+// defaults contain attributes of Model (id, created, updated, deleted), which the model has
+type ModelConfigProvider interface {
+	ModelConfig(defaults ModelConfig) ModelConfig
+}
+
+// modelConfig, returns configuration of generated attributes of given model
+func (a *DynamoAccess) modelConfig(item interface{}) ModelConfig {
+	config := ModelConfig{TimestampFormat: TimestampUnix, IDGenerator: a.idGenerator}
+
+	attributes := map[string]bool{}
+	if t := reflect.TypeOf(item); t != nil {
+		fields, _ := modelFields(t)
+		for _, field := range fields {
+			attributes[field.attribute] = true
+		}
+	}
+
+	if attributes["id"] {
+		config.IdAttribute = "id"
+	}
+	if attributes["created"] {
+		config.CreatedAttribute = "created"
+	}
+	if attributes["updated"] {
+		config.UpdatedAttribute = "updated"
+	}
+	if attributes["deleted"] {
+		config.DeletedAttribute = "deleted"
+	}
+
+	if provider, ok := item.(ModelConfigProvider); ok {
+		return provider.ModelConfig(config)
+	} else if t := modelType(item); t != nil {
+		if provider, ok := reflect.New(t).Interface().(ModelConfigProvider); ok {
+			return provider.ModelConfig(config)
+		}
+	}
+
+	return config
+}
+
+// newID, generates id by configured generator
+func (c ModelConfig) newID() (string, error) {
+	if c.IDGenerator == nil {
+		return UUIDGenerator{}.NewID()
+	}
+
+	return c.IDGenerator.NewID()
+}
+
+// timestamp, given time as attribute value in configured format
+func (c ModelConfig) timestamp(t time.Time) dynamodb.AttributeValue {
+	switch c.TimestampFormat {
+	case TimestampUnixMilli:
+		return dynamodb.AttributeValue{N: aws.String(fmt.Sprint(t.UnixNano() / int64(time.Millisecond)))}
+	case TimestampRFC3339:
+		return dynamodb.AttributeValue{S: aws.String(t.UTC().Format(time.RFC3339))}
+	}
+
+	return dynamodb.AttributeValue{N: aws.String(fmt.Sprint(t.Unix()))}
+}
+
+// needsId, id attribute is configured and the item has no id yet
+func (c ModelConfig) needsId(av map[string]dynamodb.AttributeValue) bool {
+	if c.IdAttribute == "" {
+		return false
+	}
+
+	id, ok := av[c.IdAttribute]
+	return !ok || (id.NULL != nil && *id.NULL) || (id.S != nil && *id.S == "")
+}
+
+// isDeleted, item has deleted timestamp set
+func (c ModelConfig) isDeleted(av map[string]dynamodb.AttributeValue) bool {
+	if c.DeletedAttribute == "" {
+		return false
+	}
+
+	deleted := av[c.DeletedAttribute]
+	return (deleted.N != nil && *deleted.N != "0") || (deleted.S != nil && *deleted.S != "")
+}
+
+// notDeleted, filter of items without deleted timestamp
+func (c ModelConfig) notDeleted() expression.ConditionBuilder {
+	if c.TimestampFormat == TimestampRFC3339 {
+		return expression.Name(c.DeletedAttribute).AttributeNotExists().
+			Or(expression.Name(c.DeletedAttribute).AttributeType(expression.Null))
+	}
+
+	return expression.Name(c.DeletedAttribute).Equal(expression.Value(0))
+}
@@ -0,0 +1,70 @@
+package godynamo
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type ConfigSuite struct {
+	suite.Suite
+
+	access *DynamoAccess
+}
+
+func (t *ConfigSuite) SetupSuite() {
+	t.access = &DynamoAccess{tablePrefix: "config_"}
+}
+
+func (t *ConfigSuite) TestDefaultModelConfig() {
+	t.Equal(ModelConfig{
+		IdAttribute:      "id",
+		CreatedAttribute: "created",
+		UpdatedAttribute: "updated",
+		DeletedAttribute: "deleted",
+		TimestampFormat:  TimestampUnix,
+	}, t.access.modelConfig(&aaa{}))
+
+	// user does not embed Model, nothing is generated
+	t.Equal(ModelConfig{TimestampFormat: TimestampUnix}, t.access.modelConfig(&user{}))
+}
+
+func (t *ConfigSuite) TestModelConfigProvider() {
+	t.Equal(ModelConfig{
+		CreatedAttribute: "created_at",
+		UpdatedAttribute: "updated_at",
+		TimestampFormat:  TimestampRFC3339,
+	}, t.access.modelConfig(&kkk{}))
+}
+
+func (t *ConfigSuite) TestTimestamp() {
+	at := time.Date(2020, 2, 3, 4, 5, 6, 7000000, time.FixedZone("CET", 3600))
+
+	t.Equal("1580699106", *ModelConfig{TimestampFormat: TimestampUnix}.timestamp(at).N)
+	t.Equal("1580699106007", *ModelConfig{TimestampFormat: TimestampUnixMilli}.timestamp(at).N)
+	t.Equal("2020-02-03T03:05:06Z", *ModelConfig{TimestampFormat: TimestampRFC3339}.timestamp(at).S)
+}
+
+func (t *ConfigSuite) TestNeedsId() {
+	config := ModelConfig{IdAttribute: "id"}
+
+	t.True(config.needsId(map[string]dynamodb.AttributeValue{}))
+	t.True(config.needsId(map[string]dynamodb.AttributeValue{"id": {NULL: aws.Bool(true)}}))
+	t.False(config.needsId(map[string]dynamodb.AttributeValue{"id": {S: aws.String("1")}}))
+	t.False(ModelConfig{}.needsId(map[string]dynamodb.AttributeValue{}))
+}
+
+func (t *ConfigSuite) TestIsDeleted() {
+	config := ModelConfig{DeletedAttribute: "deleted"}
+
+	t.False(config.isDeleted(map[string]dynamodb.AttributeValue{"deleted": {N: aws.String("0")}}))
+	t.True(config.isDeleted(map[string]dynamodb.AttributeValue{"deleted": {N: aws.String("1580699106")}}))
+	t.True(config.isDeleted(map[string]dynamodb.AttributeValue{"deleted": {S: aws.String("2020-02-03T03:05:06Z")}}))
+	t.False(ModelConfig{}.isDeleted(map[string]dynamodb.AttributeValue{"deleted": {N: aws.String("1")}}))
+}
+
+func TestConfigSuite(t *testing.T) {
+	suite.Run(t, &ConfigSuite{})
+}
@@ -0,0 +1,62 @@
+package godynamo
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// Error, error of db translated to one of ErrConditionFailed, ErrThrottled, ErrTableNotFound,
+// ErrTransactionCanceled or ErrValidation, cause is the original error of aws sdk
+type Error struct {
+	Kind      error
+	Operation OperationName
+	Table     string
+	Cause     error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Kind, e.Cause)
+}
+
+// Is, matches kind of the error
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// errorKinds, aws error codes and kinds they are translated to
+var errorKinds = map[string]error{
+	dynamodb.ErrCodeConditionalCheckFailedException:        ErrConditionFailed,
+	dynamodb.ErrCodeProvisionedThroughputExceededException: ErrThrottled,
+	"ThrottlingException":                                  ErrThrottled,
+	"RequestLimitExceeded":                                 ErrThrottled,
+	dynamodb.ErrCodeResourceNotFoundException:              ErrTableNotFound,
+	"TransactionCanceledException":                         ErrTransactionCanceled,
+	"ValidationException":                                  ErrValidation,
+}
+
+// translateError, wraps aws error of known code into *Error, other errors are returned as they are
+func translateError(op *Operation, err error) error {
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return err
+	}
+
+	kind, ok := errorKinds[awsErr.Code()]
+	if !ok {
+		return err
+	}
+
+	return &Error{Kind: kind, Operation: op.Name, Table: op.Table, Cause: err}
+}
+
+// translated, handler returning translated errors
+func translated(handler Handler) Handler {
+	return func(op *Operation) error {
+		return translateError(op, handler(op))
+	}
+}
@@ -0,0 +1,74 @@
+package godynamo
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
+	"github.com/aws/aws-sdk-go-v2/aws/defaults"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type ErrorSuite struct {
+	suite.Suite
+}
+
+func (t *ErrorSuite) TestTranslateError() {
+	op := &Operation{Name: OperationCreate, Table: "x_aaa"}
+
+	candidates := []struct {
+		code string
+		kind error
+	}{
+		{dynamodb.ErrCodeConditionalCheckFailedException, ErrConditionFailed},
+		{dynamodb.ErrCodeProvisionedThroughputExceededException, ErrThrottled},
+		{"ThrottlingException", ErrThrottled},
+		{dynamodb.ErrCodeResourceNotFoundException, ErrTableNotFound},
+		{"TransactionCanceledException", ErrTransactionCanceled},
+		{"ValidationException", ErrValidation},
+	}
+
+	for _, candidate := range candidates {
+		cause := awserr.New(candidate.code, "message", nil)
+		err := translateError(op, cause)
+
+		t.True(errors.Is(err, candidate.kind), candidate.code)
+		t.False(errors.Is(err, ErrNotFound), candidate.code)
+
+		var awsErr awserr.Error
+		t.True(errors.As(err, &awsErr), candidate.code)
+		t.Equal(candidate.code, awsErr.Code())
+
+		var typed *Error
+		t.True(errors.As(err, &typed), candidate.code)
+		t.Equal(OperationCreate, typed.Operation)
+		t.Equal("x_aaa", typed.Table)
+	}
+
+	other := awserr.New(dynamodb.ErrCodeInternalServerError, "message", nil)
+	t.Equal(other, translateError(op, other))
+	t.Equal(ErrNotFound, translateError(op, ErrNotFound))
+	t.Nil(translateError(op, nil))
+}
+
+func (t *ErrorSuite) TestInterceptedErrors() {
+	access := NewDynamoAccess(defaults.Config(), "x_", WithInterceptors(func(op *Operation, next Handler) error {
+		err := next(op)
+		t.True(errors.Is(err, ErrThrottled))
+		return err
+	}))
+
+	err := access.intercept(&Operation{Name: OperationScan}, func(op *Operation) error {
+		return awserr.New(dynamodb.ErrCodeProvisionedThroughputExceededException, "slow down", nil)
+	})
+	t.True(errors.Is(err, ErrThrottled))
+	t.EqualError(err, "request throttled: ProvisionedThroughputExceededException: slow down")
+}
+
+func (t *ErrorSuite) TestSchemaErrorIsValidation() {
+	t.True(errors.Is(ValidateModel(&noHash{}), ErrValidation))
+}
+
+func TestErrorSuite(t *testing.T) {
+	suite.Run(t, new(ErrorSuite))
+}
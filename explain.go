@@ -0,0 +1,330 @@
+package godynamo
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
+	"strings"
+)
+
+// ReadCost, rough estimate of items read by request, compared to items it returns
+type ReadCost int
+
+const (
+	// ReadMatching, only items matching key condition are read
+	ReadMatching ReadCost = iota
+	// ReadPartition, items matching key condition are read and filtered afterwards, all of them consume capacity
+	ReadPartition
+	// ReadTable, every item of the table or index is read and filtered afterwards
+	ReadTable
+)
+
+func (c ReadCost) String() string {
+	switch c {
+	case ReadMatching:
+		return "matching items"
+	case ReadPartition:
+		return "items of key condition"
+	}
+
+	return "whole table"
+}
+
+// Explanation, how request of model can be served by db, see Explain
+type Explanation struct {
+	Table string
+	// Operation, query when request can be served by key condition, scan otherwise
+	Operation OperationName
+	// Index, queried or scanned index, empty for the table
+	Index string
+	// KeyAttributes, attributes of key condition
+	KeyAttributes []string
+	// FilterAttributes, attributes of conditions applied after items are read
+	FilterAttributes []string
+	// FullScan, every item of the table or index has to be read
+	FullScan bool
+	Cost     ReadCost
+}
+
+func (e Explanation) String() string {
+	target := "table " + e.Table
+	if e.Index != "" {
+		target = "index " + e.Index + " of " + target
+	}
+
+	description := fmt.Sprintf("%s of %s", e.Operation, target)
+	if e.FullScan {
+		description = "full " + description
+	}
+	if len(e.KeyAttributes) > 0 {
+		description += " by " + strings.Join(e.KeyAttributes, ", ")
+	}
+	if len(e.FilterAttributes) > 0 {
+		description += ", filter " + strings.Join(e.FilterAttributes, ", ")
+	}
+
+	return description + ", reads " + e.Cost.String()
+}
+
+// Explain, describes how request of input on the table of item can be served, query is explained by its key condition,
+// request without key condition is explained as query when filter compares hash key of the table or index by equality,
+// otherwise it requires full scan
+func (a *DynamoAccess) Explain(item interface{}, input RequestInput) (*Explanation, error) {
+	schema, err := a.Schema(item)
+	if err != nil {
+		return nil, err
+	}
+
+	names := input.Expr.Names()
+	explanation := &Explanation{Table: schema.Table, Index: input.IndexName}
+
+	if input.Expr.KeyCondition() != nil {
+		explanation.Operation = OperationQuery
+		explanation.KeyAttributes = keyConditionAttributes(input.Expr)
+		if explanation.Index == "" {
+			explanation.Index, err = schema.SelectIndex(explanation.KeyAttributes...)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if filter := input.Expr.Filter(); filter != nil {
+			explanation.FilterAttributes = placeholderNames(*filter, names)
+		}
+	} else {
+		var conjuncts []explainedCondition
+		if filter := input.Expr.Filter(); filter != nil && *filter != "" {
+			conjuncts = explainConditions(*filter, names)
+		}
+
+		explanation.Operation = OperationScan
+		key, ok := KeySchema{}, false
+		if explanation.Index, key, ok = schema.keyOfConditions(conjuncts, input.IndexName); ok {
+			explanation.Operation = OperationQuery
+			explanation.KeyAttributes = []string{key.Hash}
+			if key.Range != "" && hasCondition(conjuncts, key.Range, true) {
+				explanation.KeyAttributes = append(explanation.KeyAttributes, key.Range)
+			} else {
+				key.Range = ""
+			}
+		}
+
+		for _, c := range conjuncts {
+			// conditions of key condition are not filtered
+			if ok && c.usable && ((c.attributes[0] == key.Hash && c.equal) || c.attributes[0] == key.Range) {
+				continue
+			}
+			for _, attribute := range c.attributes {
+				if !containsString(explanation.FilterAttributes, attribute) {
+					explanation.FilterAttributes = append(explanation.FilterAttributes, attribute)
+				}
+			}
+		}
+	}
+
+	switch {
+	case explanation.Operation == OperationScan:
+		explanation.FullScan, explanation.Cost = true, ReadTable
+	case len(explanation.FilterAttributes) > 0:
+		explanation.Cost = ReadPartition
+	default:
+		explanation.Cost = ReadMatching
+	}
+
+	return explanation, nil
+}
+
+// ExplainFilter, describes how ScanByFilter of the filter can be served
+func (a *DynamoAccess) ExplainFilter(item interface{}, filt expression.ConditionBuilder) (*Explanation, error) {
+	if config := a.modelConfig(item); config.DeletedAttribute != "" {
+		filt = filt.And(config.notDeleted())
+	}
+
+	expr, err := expression.NewBuilder().WithFilter(filt).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return a.Explain(item, RequestInput{Expr: expr})
+}
+
+// explainedCondition, one condition of conjunction in filter
+type explainedCondition struct {
+	attributes []string
+	// usable, condition compares one attribute the way key condition can
+	usable bool
+	// equal, condition compares attribute by equality, so it can be used on hash key
+	equal bool
+}
+
+// keyOfConditions, table or index which hash key is compared by equality in conditions,
+// the table is preferred, then index with range key compared as well, given index is the only candidate
+func (s *TableSchema) keyOfConditions(conditions []explainedCondition, index string) (string, KeySchema, bool) {
+	if index != "" {
+		key, ok := s.indexKey(index)
+		return index, key, ok && hasCondition(conditions, key.Hash, false)
+	}
+
+	if hasCondition(conditions, s.Key.Hash, false) {
+		return "", s.Key, true
+	}
+
+	name, key, found := "", KeySchema{}, false
+	for _, candidate := range append(append([]IndexSchema{}, s.GlobalIndexes...), s.LocalIndexes...) {
+		if !hasCondition(conditions, candidate.Key.Hash, false) {
+			continue
+		}
+
+		ranged := candidate.Key.Range != "" && hasCondition(conditions, candidate.Key.Range, true)
+		if !found || (ranged && !(key.Range != "" && hasCondition(conditions, key.Range, true))) {
+			name, key, found = candidate.Name, candidate.Key, true
+		}
+	}
+
+	return name, key, found
+}
+
+// hasCondition, whether attribute is compared by equality, or any comparison allowed on range key
+func hasCondition(conditions []explainedCondition, attribute string, rangeKey bool) bool {
+	for _, c := range conditions {
+		if c.usable && c.attributes[0] == attribute && (c.equal || rangeKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// explainConditions, conditions of conjunction of filter expression, whole filter is one condition when it is not conjunction
+func explainConditions(filter string, names map[string]string) []explainedCondition {
+	var conditions []explainedCondition
+	for _, conjunct := range conjuncts(tokenize(filter)) {
+		c := explainedCondition{attributes: placeholderNames(strings.Join(conjunct, " "), names)}
+		c.usable, c.equal = keyComparison(conjunct)
+		c.usable = c.usable && len(c.attributes) == 1
+		conditions = append(conditions, c)
+	}
+
+	return conditions
+}
+
+// conjuncts, operands of top level AND of tokens, nested conjunctions are flattened
+func conjuncts(tokens []string) [][]string {
+	for len(tokens) > 1 && tokens[0] == "(" && closingParen(tokens, 0) == len(tokens)-1 {
+		tokens = tokens[1 : len(tokens)-1]
+	}
+
+	var parts [][]string
+	depth, between, start := 0, false, 0
+	for i, token := range tokens {
+		switch {
+		case token == "(":
+			depth++
+		case token == ")":
+			depth--
+		case depth > 0:
+		case token == "OR" || token == "NOT":
+			return [][]string{tokens}
+		case token == "BETWEEN":
+			between = true
+		case token == "AND" && between:
+			between = false
+		case token == "AND":
+			parts = append(parts, tokens[start:i])
+			start = i + 1
+		}
+	}
+
+	if len(parts) == 0 {
+		return [][]string{tokens}
+	}
+
+	var flattened [][]string
+	for _, part := range append(parts, tokens[start:]) {
+		flattened = append(flattened, conjuncts(part)...)
+	}
+
+	return flattened
+}
+
+// keyComparison, whether tokens compare one attribute the way key condition can, and whether by equality
+func keyComparison(tokens []string) (bool, bool) {
+	switch {
+	case len(tokens) == 3 && isName(tokens[0]) && rangeOperators[Operator(tokens[1])]:
+		return true, tokens[1] == string(Eq)
+	case len(tokens) == 5 && isName(tokens[0]) && tokens[1] == string(Between) && tokens[3] == "AND":
+		return true, false
+	case len(tokens) == 6 && tokens[0] == string(BeginsWith) && tokens[1] == "(" && isName(tokens[2]):
+		return true, false
+	}
+
+	return false, false
+}
+
+func isName(token string) bool {
+	return strings.HasPrefix(token, "#") && !strings.Contains(token, ".")
+}
+
+func closingParen(tokens []string, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// tokenize, splits expression into names, values, keywords, operators and parentheses
+func tokenize(expr string) []string {
+	var tokens []string
+	current := strings.Builder{}
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range expr {
+		switch r {
+		case ' ', '\t', '\n':
+			flush()
+		case '(', ')', ',':
+			flush()
+			tokens = append(tokens, string(r))
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// placeholderNames, unique attribute names of placeholders in expression
+func placeholderNames(expr string, names map[string]string) []string {
+	var attributes []string
+	for _, placeholder := range namePlaceholder.FindAllString(expr, -1) {
+		if name, ok := names[placeholder]; ok && !containsString(attributes, name) {
+			attributes = append(attributes, name)
+		}
+	}
+
+	return attributes
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
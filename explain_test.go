@@ -0,0 +1,129 @@
+package godynamo
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws/defaults"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type ExplainSuite struct {
+	suite.Suite
+
+	access *DynamoAccess
+}
+
+func (t *ExplainSuite) SetupTest() {
+	t.access = NewDynamoAccess(defaults.Config(), "x_")
+}
+
+func (t *ExplainSuite) TestExplainFilter() {
+	candidates := []struct {
+		item     interface{}
+		filter   expression.ConditionBuilder
+		expected Explanation
+	}{
+		{
+			&user{},
+			expression.Name("email").Equal(expression.Value("john@gmail.com")),
+			Explanation{Table: "x_user", Operation: OperationQuery, KeyAttributes: []string{"email"}, Cost: ReadMatching},
+		},
+		{
+			&[]user{},
+			expression.Name("created_at").Equal(expression.Value(1)).
+				And(expression.Name("first_name").BeginsWith("J")).
+				And(expression.Name("last_name").Equal(expression.Value("Doe"))),
+			Explanation{
+				Table:            "x_user",
+				Operation:        OperationQuery,
+				Index:            "created_at_first_name_index",
+				KeyAttributes:    []string{"created_at", "first_name"},
+				FilterAttributes: []string{"last_name"},
+				Cost:             ReadPartition,
+			},
+		},
+		{
+			&[]eee{},
+			expression.Name("eec").Equal(expression.Value(1)).And(expression.Name("eeb").Between(expression.Value(1), expression.Value(2))),
+			Explanation{Table: "x_eee", Operation: OperationQuery, Index: "index2", KeyAttributes: []string{"eec", "eeb"}, FilterAttributes: []string{"deleted"}, Cost: ReadPartition},
+		},
+		{
+			&[]eee{},
+			expression.Name("eeb").GreaterThan(expression.Value(1)),
+			Explanation{Table: "x_eee", Operation: OperationScan, FilterAttributes: []string{"eeb", "deleted"}, FullScan: true, Cost: ReadTable},
+		},
+		{
+			&[]eee{},
+			expression.Name("eea").Equal(expression.Value("a")).Or(expression.Name("eec").Equal(expression.Value(1))),
+			Explanation{Table: "x_eee", Operation: OperationScan, FilterAttributes: []string{"eea", "eec", "deleted"}, FullScan: true, Cost: ReadTable},
+		},
+		{
+			&[]eee{},
+			expression.Name("eea").NotEqual(expression.Value("a")),
+			Explanation{Table: "x_eee", Operation: OperationScan, FilterAttributes: []string{"eea", "deleted"}, FullScan: true, Cost: ReadTable},
+		},
+	}
+
+	for _, candidate := range candidates {
+		explanation, err := t.access.ExplainFilter(candidate.item, candidate.filter)
+		t.Nil(err)
+		t.Equal(candidate.expected, *explanation)
+	}
+}
+
+func (t *ExplainSuite) TestExplainQuery() {
+	expr, err := expression.NewBuilder().
+		WithKeyCondition(expression.Key("eea").Equal(expression.Value("a"))).
+		WithFilter(expression.Name("eec").GreaterThan(expression.Value(1))).
+		Build()
+	t.Nil(err)
+
+	explanation, err := t.access.Explain(&[]eee{}, RequestInput{Expr: expr})
+	t.Nil(err)
+	t.Equal(Explanation{
+		Table:            "x_eee",
+		Operation:        OperationQuery,
+		Index:            "index",
+		KeyAttributes:    []string{"eea"},
+		FilterAttributes: []string{"eec"},
+		Cost:             ReadPartition,
+	}, *explanation)
+	t.Equal("query of index index of table x_eee by eea, filter eec, reads items of key condition", explanation.String())
+
+	expr, err = expression.NewBuilder().
+		WithKeyCondition(expression.Key("eeb").Equal(expression.Value(1))).
+		Build()
+	t.Nil(err)
+
+	_, err = t.access.Explain(&[]eee{}, RequestInput{Expr: expr})
+	t.True(errors.Is(err, ErrNoMatchingIndex))
+}
+
+func (t *ExplainSuite) TestExplainScan() {
+	explanation, err := t.access.Explain(&[]eee{}, RequestInput{})
+	t.Nil(err)
+	t.True(explanation.FullScan)
+	t.Equal("full scan of table x_eee, reads whole table", explanation.String())
+
+	expr, err := expression.NewBuilder().WithFilter(expression.Name("eea").Equal(expression.Value("a"))).Build()
+	t.Nil(err)
+
+	explanation, err = t.access.Explain(&[]eee{}, RequestInput{Expr: expr, IndexName: "index2"})
+	t.Nil(err)
+	t.Equal("full scan of index index2 of table x_eee, filter eea, reads whole table", explanation.String())
+}
+
+func (t *ExplainSuite) TestExplainFilterWithModelConfig() {
+	explanation, err := t.access.ExplainFilter(&[]mmm{}, expression.Name("mma").Equal(expression.Value("a")))
+	t.Nil(err)
+	t.Equal([]string{"mma", "removed"}, explanation.FilterAttributes)
+
+	explanation, err = t.access.ExplainFilter(&[]kkk{}, expression.Name("value").Equal(expression.Value("a")))
+	t.Nil(err)
+	t.Equal([]string{"value"}, explanation.FilterAttributes)
+}
+
+func TestExplainSuite(t *testing.T) {
+	suite.Run(t, new(ExplainSuite))
+}
@@ -0,0 +1,322 @@
+package godynamo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"regexp"
+	"strings"
+)
+
+var (
+	cloudFormationIdRegexp = regexp.MustCompile(`[^a-zA-Z0-9]`)
+	terraformIdRegexp      = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+)
+
+type cloudFormationTemplate struct {
+	AWSTemplateFormatVersion string                            `json:"AWSTemplateFormatVersion"`
+	Resources                map[string]cloudFormationResource `json:"Resources"`
+}
+
+type cloudFormationResource struct {
+	Type       string                   `json:"Type"`
+	Properties cloudFormationProperties `json:"Properties"`
+}
+
+type cloudFormationProperties struct {
+	TableName                        string                             `json:"TableName"`
+	AttributeDefinitions             []cloudFormationAttribute          `json:"AttributeDefinitions"`
+	KeySchema                        []cloudFormationKey                `json:"KeySchema"`
+	GlobalSecondaryIndexes           []cloudFormationIndex              `json:"GlobalSecondaryIndexes,omitempty"`
+	LocalSecondaryIndexes            []cloudFormationIndex              `json:"LocalSecondaryIndexes,omitempty"`
+	ProvisionedThroughput            cloudFormationThroughput           `json:"ProvisionedThroughput"`
+	TimeToLiveSpecification          *cloudFormationTimeToLive          `json:"TimeToLiveSpecification,omitempty"`
+	StreamSpecification              *cloudFormationStream              `json:"StreamSpecification,omitempty"`
+	SSESpecification                 *cloudFormationSSE                 `json:"SSESpecification,omitempty"`
+	PointInTimeRecoverySpecification *cloudFormationPointInTimeRecovery `json:"PointInTimeRecoverySpecification,omitempty"`
+	Tags                             []cloudFormationTag                `json:"Tags,omitempty"`
+}
+
+type cloudFormationStream struct {
+	StreamViewType string `json:"StreamViewType"`
+}
+
+type cloudFormationSSE struct {
+	SSEEnabled bool `json:"SSEEnabled"`
+}
+
+type cloudFormationPointInTimeRecovery struct {
+	PointInTimeRecoveryEnabled bool `json:"PointInTimeRecoveryEnabled"`
+}
+
+type cloudFormationTag struct {
+	Key   string `json:"Key"`
+	Value string `json:"Value"`
+}
+
+type cloudFormationTimeToLive struct {
+	AttributeName string `json:"AttributeName"`
+	Enabled       bool   `json:"Enabled"`
+}
+
+type cloudFormationAttribute struct {
+	AttributeName string `json:"AttributeName"`
+	AttributeType string `json:"AttributeType"`
+}
+
+type cloudFormationKey struct {
+	AttributeName string `json:"AttributeName"`
+	KeyType       string `json:"KeyType"`
+}
+
+type cloudFormationIndex struct {
+	IndexName             string                    `json:"IndexName"`
+	KeySchema             []cloudFormationKey       `json:"KeySchema"`
+	Projection            cloudFormationProjection  `json:"Projection"`
+	ProvisionedThroughput *cloudFormationThroughput `json:"ProvisionedThroughput,omitempty"`
+}
+
+type cloudFormationProjection struct {
+	ProjectionType string `json:"ProjectionType"`
+}
+
+type cloudFormationThroughput struct {
+	ReadCapacityUnits  int64 `json:"ReadCapacityUnits"`
+	WriteCapacityUnits int64 `json:"WriteCapacityUnits"`
+}
+
+// ExportCloudFormation, returns CloudFormation template (json) with AWS::DynamoDB::Table resource for each given model
+func (a *DynamoAccess) ExportCloudFormation(items ...interface{}) ([]byte, error) {
+	template := cloudFormationTemplate{
+		AWSTemplateFormatVersion: "2010-09-09",
+		Resources:                map[string]cloudFormationResource{},
+	}
+
+	tables := map[string]string{}
+	for _, item := range items {
+		schema, err := a.Schema(item)
+		if err != nil {
+			return nil, err
+		}
+
+		// different tables may have the same logical id, e.g. a_b and aB, later one would replace the earlier
+		id := cloudFormationId(schema.Table)
+		if table, ok := tables[id]; ok && table != schema.Table {
+			return nil, fmt.Errorf("%w: %s of tables %s and %s", ErrDuplicateResourceId, id, table, schema.Table)
+		}
+		tables[id] = schema.Table
+
+		properties := cloudFormationProperties{
+			TableName:             schema.Table,
+			KeySchema:             cloudFormationKeys(schema.Key),
+			ProvisionedThroughput: cloudFormationThroughputOf(schema.Throughput),
+		}
+
+		if schema.TTL != "" {
+			properties.TimeToLiveSpecification = &cloudFormationTimeToLive{
+				AttributeName: schema.TTL,
+				Enabled:       true,
+			}
+		}
+
+		if schema.Settings.Stream != "" {
+			properties.StreamSpecification = &cloudFormationStream{StreamViewType: string(schema.Settings.Stream)}
+		}
+
+		if schema.Settings.Encryption {
+			properties.SSESpecification = &cloudFormationSSE{SSEEnabled: true}
+		}
+
+		if schema.Settings.PointInTimeRecovery {
+			properties.PointInTimeRecoverySpecification = &cloudFormationPointInTimeRecovery{PointInTimeRecoveryEnabled: true}
+		}
+
+		for _, tag := range schema.Settings.tags() {
+			properties.Tags = append(properties.Tags, cloudFormationTag{Key: *tag.Key, Value: *tag.Value})
+		}
+
+		for _, attribute := range schema.Attributes {
+			properties.AttributeDefinitions = append(properties.AttributeDefinitions, cloudFormationAttribute{
+				AttributeName: attribute.Name,
+				AttributeType: string(attribute.Type),
+			})
+		}
+
+		for _, index := range schema.GlobalIndexes {
+			cfIndex := cloudFormationIndex{
+				IndexName:  index.Name,
+				KeySchema:  cloudFormationKeys(index.Key),
+				Projection: cloudFormationProjection{ProjectionType: string(index.Projection)},
+			}
+			if index.Throughput != nil {
+				throughput := cloudFormationThroughputOf(*index.Throughput)
+				cfIndex.ProvisionedThroughput = &throughput
+			}
+
+			properties.GlobalSecondaryIndexes = append(properties.GlobalSecondaryIndexes, cfIndex)
+		}
+
+		for _, index := range schema.LocalIndexes {
+			properties.LocalSecondaryIndexes = append(properties.LocalSecondaryIndexes, cloudFormationIndex{
+				IndexName:  index.Name,
+				KeySchema:  cloudFormationKeys(index.Key),
+				Projection: cloudFormationProjection{ProjectionType: string(index.Projection)},
+			})
+		}
+
+		template.Resources[id] = cloudFormationResource{
+			Type:       "AWS::DynamoDB::Table",
+			Properties: properties,
+		}
+	}
+
+	return json.MarshalIndent(template, "", "  ")
+}
+
+// ExportTerraform, returns terraform (hcl) with aws_dynamodb_table resource for each given model
+func (a *DynamoAccess) ExportTerraform(items ...interface{}) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+
+	for i, item := range items {
+		schema, err := a.Schema(item)
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			buffer.WriteString("\n")
+		}
+
+		fmt.Fprintf(buffer, "resource \"aws_dynamodb_table\" %q {\n", terraformId(schema.Table))
+		fmt.Fprintf(buffer, "  name           = %q\n", schema.Table)
+		fmt.Fprintf(buffer, "  read_capacity  = %d\n", schema.Throughput.Read)
+		fmt.Fprintf(buffer, "  write_capacity = %d\n", schema.Throughput.Write)
+		fmt.Fprintf(buffer, "  hash_key       = %q\n", schema.Key.Hash)
+		if schema.Key.Range != "" {
+			fmt.Fprintf(buffer, "  range_key      = %q\n", schema.Key.Range)
+		}
+		if schema.Settings.Stream != "" {
+			buffer.WriteString("\n  stream_enabled   = true\n")
+			fmt.Fprintf(buffer, "  stream_view_type = %q\n", schema.Settings.Stream)
+		}
+
+		for _, attribute := range schema.Attributes {
+			buffer.WriteString("\n  attribute {\n")
+			fmt.Fprintf(buffer, "    name = %q\n", attribute.Name)
+			fmt.Fprintf(buffer, "    type = %q\n", attribute.Type)
+			buffer.WriteString("  }\n")
+		}
+
+		for _, index := range schema.GlobalIndexes {
+			buffer.WriteString("\n  global_secondary_index {\n")
+			fmt.Fprintf(buffer, "    name            = %q\n", index.Name)
+			fmt.Fprintf(buffer, "    hash_key        = %q\n", index.Key.Hash)
+			if index.Key.Range != "" {
+				fmt.Fprintf(buffer, "    range_key       = %q\n", index.Key.Range)
+			}
+			if index.Throughput != nil {
+				fmt.Fprintf(buffer, "    read_capacity   = %d\n", index.Throughput.Read)
+				fmt.Fprintf(buffer, "    write_capacity  = %d\n", index.Throughput.Write)
+			}
+			fmt.Fprintf(buffer, "    projection_type = %q\n", index.Projection)
+			buffer.WriteString("  }\n")
+		}
+
+		for _, index := range schema.LocalIndexes {
+			buffer.WriteString("\n  local_secondary_index {\n")
+			fmt.Fprintf(buffer, "    name            = %q\n", index.Name)
+			fmt.Fprintf(buffer, "    range_key       = %q\n", index.Key.Range)
+			fmt.Fprintf(buffer, "    projection_type = %q\n", index.Projection)
+			buffer.WriteString("  }\n")
+		}
+
+		if schema.TTL != "" {
+			buffer.WriteString("\n  ttl {\n")
+			fmt.Fprintf(buffer, "    attribute_name = %q\n", schema.TTL)
+			buffer.WriteString("    enabled        = true\n")
+			buffer.WriteString("  }\n")
+		}
+
+		if schema.Settings.Encryption {
+			buffer.WriteString("\n  server_side_encryption {\n")
+			buffer.WriteString("    enabled = true\n")
+			buffer.WriteString("  }\n")
+		}
+
+		if schema.Settings.PointInTimeRecovery {
+			buffer.WriteString("\n  point_in_time_recovery {\n")
+			buffer.WriteString("    enabled = true\n")
+			buffer.WriteString("  }\n")
+		}
+
+		if tags := schema.Settings.tags(); len(tags) > 0 {
+			buffer.WriteString("\n  tags = {\n")
+			for _, tag := range tags {
+				fmt.Fprintf(buffer, "    %q = %q\n", *tag.Key, *tag.Value)
+			}
+			buffer.WriteString("  }\n")
+		}
+
+		buffer.WriteString("}\n")
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// ExportCreateTableInputs, returns json list of raw create table requests of given models
+func (a *DynamoAccess) ExportCreateTableInputs(items ...interface{}) ([]byte, error) {
+	inputs := make([]*dynamodb.CreateTableInput, 0, len(items))
+
+	for _, item := range items {
+		schema, err := a.Schema(item)
+		if err != nil {
+			return nil, err
+		}
+
+		inputs = append(inputs, schema.CreateTableInput())
+	}
+
+	return json.MarshalIndent(inputs, "", "  ")
+}
+
+func cloudFormationKeys(key KeySchema) []cloudFormationKey {
+	keys := []cloudFormationKey{}
+	for _, element := range key.elements() {
+		keys = append(keys, cloudFormationKey{
+			AttributeName: *element.AttributeName,
+			KeyType:       string(element.KeyType),
+		})
+	}
+
+	return keys
+}
+
+func cloudFormationThroughputOf(throughput Throughput) cloudFormationThroughput {
+	return cloudFormationThroughput{
+		ReadCapacityUnits:  throughput.Read,
+		WriteCapacityUnits: throughput.Write,
+	}
+}
+
+// cloudFormationId, logical id of resource has to be alphanumeric
+func cloudFormationId(table string) string {
+	parts := cloudFormationIdRegexp.Split(table, -1)
+	for i, part := range parts {
+		if part != "" {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+
+	return strings.Join(parts, "") + "Table"
+}
+
+// terraformId, name of resource has to start with letter or underscore
+func terraformId(table string) string {
+	id := terraformIdRegexp.ReplaceAllString(table, "_")
+	if id == "" || (id[0] >= '0' && id[0] <= '9') || id[0] == '-' {
+		id = "_" + id
+	}
+
+	return id
+}
@@ -0,0 +1,134 @@
+package godynamo
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws/defaults"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+// exportItem and export_item, models whose tables have the same logical id of cloud formation
+type exportItem struct {
+	Model
+}
+
+type export_item struct {
+	Model
+}
+
+type ExportSuite struct {
+	suite.Suite
+
+	access *DynamoAccess
+}
+
+func (t *ExportSuite) SetupSuite() {
+	t.access = NewDynamoAccess(defaults.Config(), "export_")
+}
+
+func (t *ExportSuite) TestExportCloudFormation() {
+	data, err := t.access.ExportCloudFormation(&user{}, &fff{})
+	t.Nil(err)
+
+	template := cloudFormationTemplate{}
+	t.Nil(json.Unmarshal(data, &template))
+
+	t.Len(template.Resources, 2)
+
+	userTable := template.Resources["ExportUserTable"]
+	t.Equal("AWS::DynamoDB::Table", userTable.Type)
+	t.Equal("export_user", userTable.Properties.TableName)
+	t.Equal([]cloudFormationKey{{AttributeName: "email", KeyType: "HASH"}}, userTable.Properties.KeySchema)
+	t.Len(userTable.Properties.GlobalSecondaryIndexes, 1)
+	t.Equal(int64(10), userTable.Properties.GlobalSecondaryIndexes[0].ProvisionedThroughput.ReadCapacityUnits)
+
+	fffTable := template.Resources["ExportFffTable"]
+	t.Len(fffTable.Properties.LocalSecondaryIndexes, 1)
+	t.Nil(fffTable.Properties.LocalSecondaryIndexes[0].ProvisionedThroughput)
+}
+
+func (t *ExportSuite) TestExportCloudFormationDuplicateId() {
+	_, err := t.access.ExportCloudFormation(&exportItem{}, &export_item{})
+	t.True(errors.Is(err, ErrDuplicateResourceId))
+	t.EqualError(err, "resources have the same id: ExportExportItemTable of tables export_exportItem and export_export_item")
+
+	data, err := t.access.ExportCloudFormation(&exportItem{}, &exportItem{})
+	t.Nil(err)
+
+	template := cloudFormationTemplate{}
+	t.Nil(json.Unmarshal(data, &template))
+	t.Len(template.Resources, 1)
+}
+
+func (t *ExportSuite) TestExportCloudFormationSettings() {
+	access := NewDynamoAccess(defaults.Config(), "export_", WithTableSettings(TableSettings{
+		PointInTimeRecovery: true,
+	}))
+
+	data, err := access.ExportCloudFormation(&hhh{})
+	t.Nil(err)
+
+	template := cloudFormationTemplate{}
+	t.Nil(json.Unmarshal(data, &template))
+
+	properties := template.Resources["ExportHhhTable"].Properties
+	t.Equal(&cloudFormationStream{StreamViewType: "NEW_AND_OLD_IMAGES"}, properties.StreamSpecification)
+	t.Nil(properties.SSESpecification)
+	t.True(properties.PointInTimeRecoverySpecification.PointInTimeRecoveryEnabled)
+	t.Equal([]cloudFormationTag{{Key: "team", Value: "core"}}, properties.Tags)
+}
+
+func (t *ExportSuite) TestExportTerraform() {
+	data, err := t.access.ExportTerraform(&user{})
+	t.Nil(err)
+
+	expected := `resource "aws_dynamodb_table" "export_user" {
+  name           = "export_user"
+  read_capacity  = 10
+  write_capacity = 10
+  hash_key       = "email"
+
+  attribute {
+    name = "first_name"
+    type = "S"
+  }
+
+  attribute {
+    name = "email"
+    type = "S"
+  }
+
+  attribute {
+    name = "created_at"
+    type = "N"
+  }
+
+  global_secondary_index {
+    name            = "created_at_first_name_index"
+    hash_key        = "created_at"
+    range_key       = "first_name"
+    read_capacity   = 10
+    write_capacity  = 10
+    projection_type = "ALL"
+  }
+}
+`
+
+	t.Equal(expected, string(data))
+}
+
+func (t *ExportSuite) TestExportCreateTableInputs() {
+	data, err := t.access.ExportCreateTableInputs(&user{})
+	t.Nil(err)
+
+	inputs := []map[string]interface{}{}
+	t.Nil(json.Unmarshal(data, &inputs))
+
+	t.Len(inputs, 1)
+	t.Equal("export_user", inputs[0]["TableName"])
+}
+
+func TestExportSuite(t *testing.T) {
+	suite.Run(t, &ExportSuite{})
+}
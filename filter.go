@@ -0,0 +1,629 @@
+package godynamo
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FilterError, position and description of problem in filter or key condition string
+type FilterError struct {
+	Input string
+	// Position, position of the problem in input, counted from 1
+	Position int
+	Message  string
+}
+
+// Is, filter error is invalid filter error
+func (e *FilterError) Is(target error) bool {
+	return target == ErrInvalidFilter
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("invalid filter at position %d: %s", e.Position, e.Message)
+}
+
+// ParseFilter, compiles filter string of model into condition, e.g. for ScanByFilter,
+// attributes are checked against the model, e.g.
+//
+//	status = "active" AND age BETWEEN 18 AND 30 AND contains(tags, "vip")
+//
+// comparisons are =, <>, <, <=, >, >=, BETWEEN and IN, functions are attribute_exists, attribute_not_exists,
+// attribute_type, begins_with, contains and size, conditions are combined by AND, OR, NOT and parentheses,
+// values are double quoted strings, numbers, true and false
+func ParseFilter(item interface{}, filter string) (expression.ConditionBuilder, error) {
+	p, err := newFilterParser(item, filter)
+	if err != nil {
+		return expression.ConditionBuilder{}, err
+	}
+
+	condition, err := p.or()
+	if err != nil {
+		return expression.ConditionBuilder{}, err
+	}
+
+	if err := p.end("AND, OR or end of filter"); err != nil {
+		return expression.ConditionBuilder{}, err
+	}
+
+	return condition, nil
+}
+
+// ParseKeyCondition, compiles key condition string of model, e.g. for Query, into condition on hash key
+// and optionally range key joined by AND, range key may be compared by =, <, <=, >, >=, BETWEEN or begins_with, e.g.
+//
+//	created_at = 1 AND begins_with(first_name, "J")
+func ParseKeyCondition(item interface{}, keyCondition string) (expression.KeyConditionBuilder, error) {
+	p, err := newFilterParser(item, keyCondition)
+	if err != nil {
+		return expression.KeyConditionBuilder{}, err
+	}
+
+	condition, err := p.keyComparison()
+	if err != nil {
+		return expression.KeyConditionBuilder{}, err
+	}
+
+	if p.keyword("AND") {
+		p.next()
+		rangeCondition, err := p.keyComparison()
+		if err != nil {
+			return expression.KeyConditionBuilder{}, err
+		}
+		condition = expression.KeyAnd(condition, rangeCondition)
+	}
+
+	if err := p.end("end of key condition"); err != nil {
+		return expression.KeyConditionBuilder{}, err
+	}
+
+	return condition, nil
+}
+
+type filterTokenKind int
+
+const (
+	tokenEnd filterTokenKind = iota
+	tokenIdentifier
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenOpen
+	tokenClose
+	tokenComma
+)
+
+type filterToken struct {
+	kind     filterTokenKind
+	text     string
+	position int
+}
+
+func (t filterToken) String() string {
+	if t.kind == tokenEnd {
+		return "end"
+	}
+
+	return strconv.Quote(t.text)
+}
+
+type filterParser struct {
+	input      string
+	tokens     []filterToken
+	current    int
+	attributes map[string]bool
+}
+
+func newFilterParser(item interface{}, input string) (*filterParser, error) {
+	p := &filterParser{input: input, attributes: map[string]bool{}}
+
+	fields, _ := modelFields(reflect.TypeOf(item))
+	for _, field := range fields {
+		p.attributes[field.attribute] = true
+	}
+
+	tokens, err := p.tokenize()
+	if err != nil {
+		return nil, err
+	}
+	p.tokens = tokens
+
+	return p, nil
+}
+
+func (p *filterParser) tokenize() ([]filterToken, error) {
+	var tokens []filterToken
+	runes := []rune(p.input)
+
+	for i := 0; i < len(runes); {
+		r, start := runes[i], i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			tokens = append(tokens, filterToken{tokenOpen, "(", start + 1})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{tokenClose, ")", start + 1})
+			i++
+		case r == ',':
+			tokens = append(tokens, filterToken{tokenComma, ",", start + 1})
+			i++
+		case strings.ContainsRune("=<>", r):
+			i++
+			if i < len(runes) && (runes[i] == '=' || (r == '<' && runes[i] == '>')) {
+				i++
+			}
+			tokens = append(tokens, filterToken{tokenOperator, string(runes[start:i]), start + 1})
+		case r == '"':
+			for i++; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(runes) {
+				return nil, p.problem(start+1, "unterminated string")
+			}
+			i++
+			tokens = append(tokens, filterToken{tokenString, string(runes[start:i]), start + 1})
+		case unicode.IsDigit(r) || ((r == '-' || r == '.') && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			for i++; i < len(runes) && (unicode.IsDigit(runes[i]) || strings.ContainsRune(".eE", runes[i]) ||
+				((runes[i] == '-' || runes[i] == '+') && strings.ContainsRune("eE", runes[i-1]))); i++ {
+			}
+			tokens = append(tokens, filterToken{tokenNumber, string(runes[start:i]), start + 1})
+		case unicode.IsLetter(r) || r == '_':
+			for i++; i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || strings.ContainsRune("_.[]-", runes[i])); i++ {
+			}
+			tokens = append(tokens, filterToken{tokenIdentifier, string(runes[start:i]), start + 1})
+		default:
+			return nil, p.problem(start+1, fmt.Sprintf("unexpected character %q", r))
+		}
+	}
+
+	return append(tokens, filterToken{kind: tokenEnd, position: len(runes) + 1}), nil
+}
+
+func (p *filterParser) problem(position int, message string) error {
+	return &FilterError{Input: p.input, Position: position, Message: message}
+}
+
+func (p *filterParser) unexpected(expected string) error {
+	token := p.peek()
+	return p.problem(token.position, fmt.Sprintf("expected %s, got %s", expected, token))
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.current]
+}
+
+func (p *filterParser) next() filterToken {
+	token := p.tokens[p.current]
+	if token.kind != tokenEnd {
+		p.current++
+	}
+	return token
+}
+
+// keyword, whether current token is given keyword, case insensitive
+func (p *filterParser) keyword(keyword string) bool {
+	token := p.peek()
+	return token.kind == tokenIdentifier && strings.EqualFold(token.text, keyword)
+}
+
+func (p *filterParser) expect(kind filterTokenKind, expected string) (filterToken, error) {
+	if p.peek().kind != kind {
+		return filterToken{}, p.unexpected(expected)
+	}
+
+	return p.next(), nil
+}
+
+func (p *filterParser) end(expected string) error {
+	if p.peek().kind != tokenEnd {
+		return p.unexpected(expected)
+	}
+
+	return nil
+}
+
+func (p *filterParser) or() (expression.ConditionBuilder, error) {
+	condition, err := p.and()
+	if err != nil {
+		return condition, err
+	}
+
+	for p.keyword("OR") {
+		p.next()
+		right, err := p.and()
+		if err != nil {
+			return condition, err
+		}
+		condition = condition.Or(right)
+	}
+
+	return condition, nil
+}
+
+func (p *filterParser) and() (expression.ConditionBuilder, error) {
+	condition, err := p.not()
+	if err != nil {
+		return condition, err
+	}
+
+	for p.keyword("AND") {
+		p.next()
+		right, err := p.not()
+		if err != nil {
+			return condition, err
+		}
+		condition = condition.And(right)
+	}
+
+	return condition, nil
+}
+
+func (p *filterParser) not() (expression.ConditionBuilder, error) {
+	if p.keyword("NOT") {
+		p.next()
+		condition, err := p.not()
+		if err != nil {
+			return condition, err
+		}
+		return condition.Not(), nil
+	}
+
+	if p.peek().kind == tokenOpen {
+		p.next()
+		condition, err := p.or()
+		if err != nil {
+			return condition, err
+		}
+		if _, err := p.expect(tokenClose, `")"`); err != nil {
+			return condition, err
+		}
+		return condition, nil
+	}
+
+	return p.comparison()
+}
+
+func (p *filterParser) comparison() (expression.ConditionBuilder, error) {
+	token := p.peek()
+	if token.kind == tokenIdentifier && p.tokens[p.current+1].kind == tokenOpen {
+		switch strings.ToLower(token.text) {
+		case "attribute_exists", "attribute_not_exists", "attribute_type", "begins_with", "contains":
+			return p.function()
+		}
+	}
+
+	left, err := p.operand()
+	if err != nil {
+		return expression.ConditionBuilder{}, err
+	}
+
+	switch {
+	case p.keyword("BETWEEN"):
+		p.next()
+		lower, err := p.operand()
+		if err != nil {
+			return expression.ConditionBuilder{}, err
+		}
+		if !p.keyword("AND") {
+			return expression.ConditionBuilder{}, p.unexpected("AND of BETWEEN")
+		}
+		p.next()
+		upper, err := p.operand()
+		if err != nil {
+			return expression.ConditionBuilder{}, err
+		}
+		return between(left, lower, upper)
+	case p.keyword("IN"):
+		p.next()
+		if _, err := p.expect(tokenOpen, `"(" of IN`); err != nil {
+			return expression.ConditionBuilder{}, err
+		}
+		var values []expression.OperandBuilder
+		for {
+			value, err := p.operand()
+			if err != nil {
+				return expression.ConditionBuilder{}, err
+			}
+			values = append(values, value)
+			if p.peek().kind != tokenComma {
+				break
+			}
+			p.next()
+		}
+		if _, err := p.expect(tokenClose, `"," or ")" of IN`); err != nil {
+			return expression.ConditionBuilder{}, err
+		}
+		name, ok := left.(expression.NameBuilder)
+		if !ok {
+			return expression.ConditionBuilder{}, p.problem(token.position, "IN needs attribute on the left side")
+		}
+		return name.In(values[0], values[1:]...), nil
+	}
+
+	operator, err := p.expect(tokenOperator, "comparison, BETWEEN or IN")
+	if err != nil {
+		return expression.ConditionBuilder{}, err
+	}
+
+	right, err := p.operand()
+	if err != nil {
+		return expression.ConditionBuilder{}, err
+	}
+
+	switch Operator(operator.text) {
+	case Ne:
+		return expression.NotEqual(left, right), nil
+	case Lt:
+		return expression.LessThan(left, right), nil
+	case Le:
+		return expression.LessThanEqual(left, right), nil
+	case Gt:
+		return expression.GreaterThan(left, right), nil
+	case Ge:
+		return expression.GreaterThanEqual(left, right), nil
+	case Eq:
+		return expression.Equal(left, right), nil
+	}
+
+	return expression.ConditionBuilder{}, p.problem(operator.position, fmt.Sprintf("unknown operator %q", operator.text))
+}
+
+func between(operand, lower, upper expression.OperandBuilder) (expression.ConditionBuilder, error) {
+	switch o := operand.(type) {
+	case expression.NameBuilder:
+		return o.Between(lower, upper), nil
+	case expression.SizeBuilder:
+		return o.Between(lower, upper), nil
+	}
+
+	return expression.And(expression.GreaterThanEqual(operand, lower), expression.LessThanEqual(operand, upper)), nil
+}
+
+func (p *filterParser) function() (expression.ConditionBuilder, error) {
+	function := p.next()
+	p.next()
+
+	name, err := p.name()
+	if err != nil {
+		return expression.ConditionBuilder{}, err
+	}
+
+	var condition expression.ConditionBuilder
+	switch strings.ToLower(function.text) {
+	case "attribute_exists":
+		condition = name.AttributeExists()
+	case "attribute_not_exists":
+		condition = name.AttributeNotExists()
+	default:
+		if _, err := p.expect(tokenComma, fmt.Sprintf(`"," of %s`, function.text)); err != nil {
+			return condition, err
+		}
+		argument, err := p.expect(tokenString, fmt.Sprintf("string argument of %s", function.text))
+		if err != nil {
+			return condition, err
+		}
+		value, err := p.unquote(argument)
+		if err != nil {
+			return condition, err
+		}
+
+		switch strings.ToLower(function.text) {
+		case "attribute_type":
+			condition = name.AttributeType(expression.DynamoDBAttributeType(value))
+		case "begins_with":
+			condition = name.BeginsWith(value)
+		default:
+			condition = name.Contains(value)
+		}
+	}
+
+	if _, err := p.expect(tokenClose, fmt.Sprintf(`")" of %s`, function.text)); err != nil {
+		return condition, err
+	}
+
+	return condition, nil
+}
+
+// operand, attribute, size of attribute or value
+func (p *filterParser) operand() (expression.OperandBuilder, error) {
+	token := p.peek()
+
+	switch token.kind {
+	case tokenString:
+		p.next()
+		value, err := p.unquote(token)
+		if err != nil {
+			return nil, err
+		}
+		return expression.Value(value), nil
+	case tokenNumber:
+		p.next()
+		if value, err := strconv.ParseInt(token.text, 10, 64); err == nil {
+			return expression.Value(value), nil
+		}
+		value, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, p.problem(token.position, fmt.Sprintf("invalid number %q", token.text))
+		}
+		return expression.Value(value), nil
+	case tokenIdentifier:
+		switch {
+		case strings.EqualFold(token.text, "true"), strings.EqualFold(token.text, "false"):
+			p.next()
+			return expression.Value(strings.EqualFold(token.text, "true")), nil
+		case strings.EqualFold(token.text, "size") && p.tokens[p.current+1].kind == tokenOpen:
+			p.next()
+			p.next()
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(tokenClose, `")" of size`); err != nil {
+				return nil, err
+			}
+			return name.Size(), nil
+		}
+		return p.name()
+	}
+
+	return nil, p.unexpected("attribute or value")
+}
+
+// name, attribute of the model, nested attributes are checked by their top level attribute
+func (p *filterParser) name() (expression.NameBuilder, error) {
+	token := p.peek()
+	if token.kind != tokenIdentifier || isFilterKeyword(token.text) {
+		return expression.NameBuilder{}, p.unexpected("attribute")
+	}
+	p.next()
+
+	attribute := token.text
+	if i := strings.IndexAny(attribute, ".["); i >= 0 {
+		attribute = attribute[:i]
+	}
+
+	if len(p.attributes) > 0 && !p.attributes[attribute] {
+		return expression.NameBuilder{}, p.problem(token.position, fmt.Sprintf("unknown attribute %q", attribute))
+	}
+
+	return expression.Name(token.text), nil
+}
+
+func (p *filterParser) unquote(token filterToken) (string, error) {
+	value, err := strconv.Unquote(token.text)
+	if err != nil {
+		return "", p.problem(token.position, fmt.Sprintf("invalid string %s", token.text))
+	}
+
+	return value, nil
+}
+
+// keyComparison, comparison of key condition, optionally in parentheses
+func (p *filterParser) keyComparison() (expression.KeyConditionBuilder, error) {
+	if p.peek().kind == tokenOpen {
+		p.next()
+		condition, err := p.keyComparison()
+		if err != nil {
+			return condition, err
+		}
+		if _, err := p.expect(tokenClose, `")"`); err != nil {
+			return condition, err
+		}
+		return condition, nil
+	}
+
+	if p.keyword("begins_with") && p.tokens[p.current+1].kind == tokenOpen {
+		p.next()
+		p.next()
+		name, err := p.keyName()
+		if err != nil {
+			return expression.KeyConditionBuilder{}, err
+		}
+		if _, err := p.expect(tokenComma, `"," of begins_with`); err != nil {
+			return expression.KeyConditionBuilder{}, err
+		}
+		argument, err := p.expect(tokenString, "string argument of begins_with")
+		if err != nil {
+			return expression.KeyConditionBuilder{}, err
+		}
+		prefix, err := p.unquote(argument)
+		if err != nil {
+			return expression.KeyConditionBuilder{}, err
+		}
+		if _, err := p.expect(tokenClose, `")" of begins_with`); err != nil {
+			return expression.KeyConditionBuilder{}, err
+		}
+		return name.BeginsWith(prefix), nil
+	}
+
+	name, err := p.keyName()
+	if err != nil {
+		return expression.KeyConditionBuilder{}, err
+	}
+
+	if p.keyword("BETWEEN") {
+		p.next()
+		lower, err := p.keyValue()
+		if err != nil {
+			return expression.KeyConditionBuilder{}, err
+		}
+		if !p.keyword("AND") {
+			return expression.KeyConditionBuilder{}, p.unexpected("AND of BETWEEN")
+		}
+		p.next()
+		upper, err := p.keyValue()
+		if err != nil {
+			return expression.KeyConditionBuilder{}, err
+		}
+		return name.Between(lower, upper), nil
+	}
+
+	operator := p.peek()
+	if operator.kind != tokenOperator || !rangeOperators[Operator(operator.text)] {
+		return expression.KeyConditionBuilder{}, p.unexpected("=, <, <=, >, >= or BETWEEN of key condition")
+	}
+	p.next()
+
+	value, err := p.keyValue()
+	if err != nil {
+		return expression.KeyConditionBuilder{}, err
+	}
+
+	switch Operator(operator.text) {
+	case Lt:
+		return name.LessThan(value), nil
+	case Le:
+		return name.LessThanEqual(value), nil
+	case Gt:
+		return name.GreaterThan(value), nil
+	case Ge:
+		return name.GreaterThanEqual(value), nil
+	}
+
+	return name.Equal(value), nil
+}
+
+func (p *filterParser) keyName() (expression.KeyBuilder, error) {
+	token := p.peek()
+	if _, err := p.name(); err != nil {
+		return expression.KeyBuilder{}, err
+	}
+
+	if strings.ContainsAny(token.text, ".[") {
+		return expression.KeyBuilder{}, p.problem(token.position, fmt.Sprintf("key attribute %q can not be nested", token.text))
+	}
+
+	return expression.Key(token.text), nil
+}
+
+func (p *filterParser) keyValue() (expression.ValueBuilder, error) {
+	token := p.peek()
+	operand, err := p.operand()
+	if err != nil {
+		return expression.ValueBuilder{}, err
+	}
+
+	value, ok := operand.(expression.ValueBuilder)
+	if !ok {
+		return expression.ValueBuilder{}, p.problem(token.position, "key attribute can be compared with value only")
+	}
+
+	return value, nil
+}
+
+func isFilterKeyword(text string) bool {
+	switch strings.ToUpper(text) {
+	case "AND", "OR", "NOT", "BETWEEN", "IN":
+		return true
+	}
+
+	return false
+}
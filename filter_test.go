@@ -0,0 +1,127 @@
+package godynamo
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type FilterSuite struct {
+	suite.Suite
+}
+
+func (t *FilterSuite) equalFilter(expected expression.ConditionBuilder, filter string) {
+	parsed, err := ParseFilter(&[]user{}, filter)
+	t.Nil(err, filter)
+
+	expectedExpr, err := expression.NewBuilder().WithFilter(expected).Build()
+	t.Nil(err)
+	parsedExpr, err := expression.NewBuilder().WithFilter(parsed).Build()
+	t.Nil(err)
+	t.Equal(expectedExpr, parsedExpr, filter)
+}
+
+func (t *FilterSuite) TestParseFilter() {
+	t.equalFilter(
+		expression.Name("last_name").Equal(expression.Value("Doe")).
+			And(expression.Name("created_at").Between(expression.Value(int64(18)), expression.Value(int64(30)))).
+			And(expression.Name("first_name").Contains("J")),
+		`last_name = "Doe" AND created_at BETWEEN 18 AND 30 AND contains(first_name, "J")`,
+	)
+
+	t.equalFilter(
+		expression.Name("email").Equal(expression.Value("a")).
+			Or(expression.Name("created_at").GreaterThan(expression.Value(1.5)).And(expression.Name("updated_at").LessThanEqual(expression.Value(int64(-3))))),
+		`email = "a" or created_at > 1.5 and updated_at <= -3`,
+	)
+
+	t.equalFilter(
+		expression.Not(expression.Name("email").NotEqual(expression.Value("a\"b")).Or(expression.Name("first_name").AttributeNotExists())).
+			And(expression.Name("last_name").In(expression.Value("Doe"), expression.Value("Roe"))),
+		`NOT (email <> "a\"b" OR attribute_not_exists(first_name)) AND last_name IN ("Doe", "Roe")`,
+	)
+
+	t.equalFilter(
+		expression.Name("addresses[0].city").BeginsWith("P").
+			And(expression.Name("authentication_tokens").Size().GreaterThan(expression.Value(int64(2)))).
+			And(expression.Name("first_name").AttributeType(expression.String)).
+			And(expression.Name("created_at").Equal(expression.Name("updated_at"))).
+			And(expression.Name("email").Equal(expression.Value(true))),
+		`begins_with(addresses[0].city, "P") AND size(authentication_tokens) > 2 AND attribute_type(first_name, "S") AND created_at = updated_at AND email = TRUE`,
+	)
+}
+
+func (t *FilterSuite) TestParseFilterFails() {
+	candidates := []struct {
+		filter   string
+		expected string
+	}{
+		{`last_name = `, `invalid filter at position 13: expected attribute or value, got end`},
+		{`lastname = "Doe"`, `invalid filter at position 1: unknown attribute "lastname"`},
+		{`email = "a" AND`, `invalid filter at position 16: expected attribute or value, got end`},
+		{`email = "a`, `invalid filter at position 9: unterminated string`},
+		{`email ~ "a"`, `invalid filter at position 7: unexpected character '~'`},
+		{`(email = "a"`, `invalid filter at position 13: expected ")", got end`},
+		{`email = "a" last_name = "b"`, `invalid filter at position 13: expected AND, OR or end of filter, got "last_name"`},
+		{`created_at BETWEEN 1 OR 2`, `invalid filter at position 22: expected AND of BETWEEN, got "OR"`},
+		{`contains(first_name, 1)`, `invalid filter at position 22: expected string argument of contains, got "1"`},
+		{`email AND "a"`, `invalid filter at position 7: expected comparison, BETWEEN or IN, got "AND"`},
+	}
+
+	for _, candidate := range candidates {
+		_, err := ParseFilter(&user{}, candidate.filter)
+		t.EqualError(err, candidate.expected, candidate.filter)
+		t.True(errors.Is(err, ErrInvalidFilter))
+
+		var filterError *FilterError
+		t.True(errors.As(err, &filterError))
+		t.Equal(candidate.filter, filterError.Input)
+	}
+}
+
+func (t *FilterSuite) TestParseKeyCondition() {
+	parsed, err := ParseKeyCondition(&[]user{}, `created_at = 1 AND begins_with(first_name, "J")`)
+	t.Nil(err)
+
+	expected := expression.Key("created_at").Equal(expression.Value(int64(1))).And(expression.Key("first_name").BeginsWith("J"))
+	expectedExpr, err := expression.NewBuilder().WithKeyCondition(expected).Build()
+	t.Nil(err)
+	parsedExpr, err := expression.NewBuilder().WithKeyCondition(parsed).Build()
+	t.Nil(err)
+	t.Equal(expectedExpr, parsedExpr)
+
+	parsed, err = ParseKeyCondition(&[]fff{}, `(id = "1") AND ffb BETWEEN "a" AND "b"`)
+	t.Nil(err)
+
+	expected = expression.Key("id").Equal(expression.Value("1")).And(expression.Key("ffb").Between(expression.Value("a"), expression.Value("b")))
+	expectedExpr, err = expression.NewBuilder().WithKeyCondition(expected).Build()
+	t.Nil(err)
+	parsedExpr, err = expression.NewBuilder().WithKeyCondition(parsed).Build()
+	t.Nil(err)
+	t.Equal(expectedExpr, parsedExpr)
+}
+
+func (t *FilterSuite) TestParseKeyConditionFails() {
+	candidates := []struct {
+		keyCondition string
+		expected     string
+	}{
+		{`email <> "a"`, `invalid filter at position 7: expected =, <, <=, >, >= or BETWEEN of key condition, got "<>"`},
+		{`email = "a" OR created_at = 1`, `invalid filter at position 13: expected end of key condition, got "OR"`},
+		{`email = "a" AND created_at = 1 AND first_name = "J"`, `invalid filter at position 32: expected end of key condition, got "AND"`},
+		{`email = created_at`, `invalid filter at position 9: key attribute can be compared with value only`},
+		{`addresses.city = "a"`, `invalid filter at position 1: key attribute "addresses.city" can not be nested`},
+		{`mail = "a"`, `invalid filter at position 1: unknown attribute "mail"`},
+	}
+
+	for _, candidate := range candidates {
+		_, err := ParseKeyCondition(&user{}, candidate.keyCondition)
+		t.EqualError(err, candidate.expected, candidate.keyCondition)
+		t.True(errors.Is(err, ErrInvalidFilter))
+	}
+}
+
+func TestFilterSuite(t *testing.T) {
+	suite.Run(t, new(FilterSuite))
+}
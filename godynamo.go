@@ -4,23 +4,72 @@ import (
 	"errors"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"time"
 )
 
 type DynamoAccess struct {
 	svc         *dynamodb.DynamoDB
 	tablePrefix string
+
+	filterExpired bool
+	tableSettings TableSettings
+	idGenerator   IDGenerator
+	clock         Clock
+	interceptors  []Interceptor
+	collectors    []*CapacityCollector
+	retryPolicy   RetryPolicy
+	rateLimiter   *RateLimiter
+	cache         *Cache
+	scanPolicy    ScanPolicy
+	scanBudget    *scanBudget
+	scanAllowed   bool
+	schemaTimeout time.Duration
+}
+
+// Option, configures behaviour of DynamoAccess
+type Option func(a *DynamoAccess)
+
+func NewDynamoAccess(config aws.Config, tablePrefix string, options ...Option) *DynamoAccess {
+	a := &DynamoAccess{svc: dynamodb.New(config), tablePrefix: tablePrefix, idGenerator: UUIDGenerator{}, clock: SystemClock{}}
+	for _, option := range options {
+		option(a)
+	}
+
+	return a
 }
 
-func NewDynamoAccess(config aws.Config, tablePrefix string) *DynamoAccess {
-	return &DynamoAccess{svc: dynamodb.New(config), tablePrefix: tablePrefix}
+// FilterExpired, items past their ttl, which are not yet deleted by db, are not returned by reads
+func FilterExpired() Option {
+	return func(a *DynamoAccess) {
+		a.filterExpired = true
+	}
 }
 
 var (
-	ErrNotPointer       = errors.New("item must be pointer")
-	ErrElemNil          = errors.New("elem is nil")
-	ErrNotFound         = errors.New("item not found")
-	ErrNotSupportedType = errors.New("not supported type")
-	ErrSlice            = errors.New("slice is prohibited")
-	ErrNotSlice         = errors.New("item has to be slice")
-	NoPaging            = map[string]dynamodb.AttributeValue{}
+	ErrNotPointer              = errors.New("item must be pointer")
+	ErrElemNil                 = errors.New("elem is nil")
+	ErrNotFound                = errors.New("item not found")
+	ErrNotSupportedType        = errors.New("not supported type")
+	ErrSlice                   = errors.New("slice is prohibited")
+	ErrNotSlice                = errors.New("item has to be slice")
+	ErrUnsupportedSchemaChange = errors.New("schema change can not be applied in place")
+	ErrNoTTL                   = errors.New("model has no ttl attribute")
+	ErrNoSoftDelete            = errors.New("model has no deleted attribute")
+	ErrConditionFailed         = errors.New("condition failed")
+	ErrThrottled               = errors.New("request throttled")
+	ErrTableNotFound           = errors.New("table not found")
+	ErrTransactionCanceled     = errors.New("transaction canceled")
+	ErrValidation              = errors.New("validation failed")
+	ErrUnprocessedItems        = errors.New("items of batch left unprocessed")
+	ErrInvalidQuery            = errors.New("invalid query")
+	ErrNoMatchingIndex         = errors.New("no index matches key condition")
+	ErrAmbiguousIndex          = errors.New("more indexes match key condition")
+	ErrScanForbidden           = errors.New("scan is forbidden")
+	ErrScanBudgetExceeded      = errors.New("scan budget exceeded")
+	ErrInvalidFilter           = errors.New("invalid filter")
+	ErrInvalidKey              = errors.New("key does not match primary key")
+	ErrTableNotActive          = errors.New("table is not active")
+	ErrStatementFailed         = errors.New("statement failed")
+	ErrDuplicateResourceId     = errors.New("resources have the same id")
+	NoPaging                   = map[string]dynamodb.AttributeValue{}
 )
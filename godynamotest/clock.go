@@ -0,0 +1,58 @@
+// Package godynamotest contains helpers for tests of code using godynamo
+package godynamotest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock, fake clock for godynamo.WithClock, which returns fixed time,
+// the time is moved by step after each call of Now, when step is set
+type Clock struct {
+	mutex sync.Mutex
+	now   time.Time
+	step  time.Duration
+}
+
+// NewClock, clock fixed at given time
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// NewSteppingClock, clock starting at given time, which moves by step after each call of Now
+func NewSteppingClock(start time.Time, step time.Duration) *Clock {
+	return &Clock{now: start, step: step}
+}
+
+func (c *Clock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+// Set, moves clock to given time
+func (c *Clock) Set(now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = now
+}
+
+// Advance, moves clock by given duration
+func (c *Clock) Advance(duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(duration)
+}
+
+// SetStep, changes duration by which is the clock moved after each call of Now
+func (c *Clock) SetStep(step time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.step = step
+}
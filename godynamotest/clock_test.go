@@ -0,0 +1,41 @@
+package godynamotest
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type ClockSuite struct {
+	suite.Suite
+}
+
+func (t *ClockSuite) TestFixedClock() {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewClock(start)
+
+	t.Equal(start, clock.Now())
+	t.Equal(start, clock.Now())
+
+	clock.Advance(time.Hour)
+	t.Equal(start.Add(time.Hour), clock.Now())
+
+	clock.Set(start)
+	t.Equal(start, clock.Now())
+}
+
+func (t *ClockSuite) TestSteppingClock() {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewSteppingClock(start, time.Second)
+
+	t.Equal(start, clock.Now())
+	t.Equal(start.Add(time.Second), clock.Now())
+
+	clock.SetStep(0)
+	t.Equal(start.Add(2*time.Second), clock.Now())
+	t.Equal(start.Add(2*time.Second), clock.Now())
+}
+
+func TestClockSuite(t *testing.T) {
+	suite.Run(t, &ClockSuite{})
+}
@@ -0,0 +1,112 @@
+package godynamo
+
+import "reflect"
+
+// BeforeCreate, called before item is created, error aborts create
+type BeforeCreate interface {
+	BeforeCreate() error
+}
+
+// AfterCreate, called after item is created
+type AfterCreate interface {
+	AfterCreate() error
+}
+
+// BeforeUpdate, called before item is updated, error aborts update
+type BeforeUpdate interface {
+	BeforeUpdate() error
+}
+
+// BeforeDelete, called before item is deleted or soft deleted, error aborts delete
+type BeforeDelete interface {
+	BeforeDelete() error
+}
+
+// AfterLoad, called on every item unmarshaled by reads
+type AfterLoad interface {
+	AfterLoad() error
+}
+
+// Validate, called before every write of item, after before hooks
+type Validate interface {
+	Validate() error
+}
+
+func beforeCreate(item interface{}) error {
+	if hook, ok := item.(BeforeCreate); ok {
+		if err := hook.BeforeCreate(); err != nil {
+			return err
+		}
+	}
+
+	return validate(item)
+}
+
+func afterCreate(item interface{}) error {
+	if hook, ok := item.(AfterCreate); ok {
+		return hook.AfterCreate()
+	}
+
+	return nil
+}
+
+func beforeUpdate(item interface{}) error {
+	if hook, ok := item.(BeforeUpdate); ok {
+		if err := hook.BeforeUpdate(); err != nil {
+			return err
+		}
+	}
+
+	return validate(item)
+}
+
+func beforeDelete(item interface{}) error {
+	if hook, ok := item.(BeforeDelete); ok {
+		return hook.BeforeDelete()
+	}
+
+	return nil
+}
+
+func validate(item interface{}) error {
+	if hook, ok := item.(Validate); ok {
+		return hook.Validate()
+	}
+
+	return nil
+}
+
+// afterLoad, calls AfterLoad on item, or on every element when item is slice
+func afterLoad(item interface{}) error {
+	if hook, ok := item.(AfterLoad); ok {
+		return hook.AfterLoad()
+	}
+
+	v := reflect.ValueOf(item)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Slice {
+		return nil
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		if elem.Kind() != reflect.Ptr {
+			elem = elem.Addr()
+		}
+
+		if elem.IsNil() {
+			continue
+		}
+
+		if hook, ok := elem.Interface().(AfterLoad); ok {
+			if err := hook.AfterLoad(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
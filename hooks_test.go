@@ -0,0 +1,56 @@
+package godynamo
+
+import (
+	"errors"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type HooksSuite struct {
+	suite.Suite
+}
+
+type loadedItem struct {
+	Loaded bool
+}
+
+func (l *loadedItem) AfterLoad() error {
+	l.Loaded = true
+	return nil
+}
+
+type failingItem struct{}
+
+func (failingItem) BeforeDelete() error {
+	return errors.New("protected")
+}
+
+func (t *HooksSuite) TestAfterLoadSlice() {
+	items := []loadedItem{{}, {}}
+	t.Nil(afterLoad(&items))
+	t.True(items[0].Loaded)
+	t.True(items[1].Loaded)
+
+	pointers := []*loadedItem{{}, nil}
+	t.Nil(afterLoad(&pointers))
+	t.True(pointers[0].Loaded)
+
+	item := &loadedItem{}
+	t.Nil(afterLoad(item))
+	t.True(item.Loaded)
+}
+
+func (t *HooksSuite) TestBeforeDelete() {
+	t.EqualError(beforeDelete(&failingItem{}), "protected")
+	t.Nil(beforeDelete(&loadedItem{}))
+}
+
+func (t *HooksSuite) TestValidate() {
+	t.Equal(errEmptyLa, beforeCreate(&lll{La: " "}))
+	t.Equal(errEmptyLa, beforeUpdate(&lll{}))
+	t.Nil(beforeCreate(&aaa{}))
+}
+
+func TestHooksSuite(t *testing.T) {
+	suite.Run(t, new(HooksSuite))
+}
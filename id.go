@@ -0,0 +1,127 @@
+package godynamo
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"github.com/gofrs/uuid"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IDGenerator, generates ids of items created by DynamoAccess
+type IDGenerator interface {
+	NewID() (string, error)
+}
+
+// WithIDGenerator, generator of ids of all models, which do not configure their own
+func WithIDGenerator(generator IDGenerator) Option {
+	return func(a *DynamoAccess) {
+		a.idGenerator = generator
+	}
+}
+
+// UUIDGenerator, random UUID version 4, default generator
+type UUIDGenerator struct{}
+
+func (UUIDGenerator) NewID() (string, error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+const (
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	base62Alphabet    = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	// ksuidEpoch, KSUID counts seconds since 2014-05-13
+	ksuidEpoch = 1400000000
+)
+
+// ULIDGenerator, 26 characters long ULID, which is lexicographically sortable by time of creation in milliseconds,
+// time is taken from Clock, system time when nil
+type ULIDGenerator struct {
+	Clock Clock
+}
+
+func (g ULIDGenerator) NewID() (string, error) {
+	id := make([]byte, 16)
+
+	ms := uint64(now(g.Clock).UnixNano() / int64(time.Millisecond))
+	binary.BigEndian.PutUint16(id[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(id[2:6], uint32(ms))
+
+	if _, err := rand.Read(id[6:]); err != nil {
+		return "", err
+	}
+
+	return encodeULID(id), nil
+}
+
+// encodeULID, Crockford's base32 of 128 bits, padded to 26 characters
+func encodeULID(id []byte) string {
+	digits := new(big.Int).SetBytes(id).Text(32)
+
+	encoded := make([]byte, len(digits))
+	for i := range digits {
+		value := strings.IndexByte("0123456789abcdefghijklmnopqrstuv", digits[i])
+		encoded[i] = crockfordAlphabet[value]
+	}
+
+	return strings.Repeat("0", 26-len(encoded)) + string(encoded)
+}
+
+// KSUIDGenerator, 27 characters long KSUID, which is lexicographically sortable by time of creation in seconds,
+// time is taken from Clock, system time when nil
+type KSUIDGenerator struct {
+	Clock Clock
+}
+
+func (g KSUIDGenerator) NewID() (string, error) {
+	id := make([]byte, 20)
+
+	binary.BigEndian.PutUint32(id[0:4], uint32(now(g.Clock).Unix()-ksuidEpoch))
+
+	if _, err := rand.Read(id[4:]); err != nil {
+		return "", err
+	}
+
+	return encodeKSUID(id), nil
+}
+
+// encodeKSUID, base62 of 160 bits, padded to 27 characters
+func encodeKSUID(id []byte) string {
+	value := new(big.Int).SetBytes(id)
+	base := big.NewInt(62)
+	remainder := new(big.Int)
+
+	encoded := make([]byte, 27)
+	for i := len(encoded) - 1; i >= 0; i-- {
+		value.DivMod(value, base, remainder)
+		encoded[i] = base62Alphabet[remainder.Int64()]
+	}
+
+	return string(encoded)
+}
+
+// SequenceGenerator, deterministic ids for tests, Prefix followed by sequence number starting by 1,
+// the number is padded, so ids are sortable
+type SequenceGenerator struct {
+	Prefix string
+
+	mutex sync.Mutex
+	last  uint64
+}
+
+func (g *SequenceGenerator) NewID() (string, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.last++
+	return fmt.Sprintf("%s%020d", g.Prefix, g.last), nil
+}
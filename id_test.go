@@ -0,0 +1,103 @@
+package godynamo
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws/defaults"
+	"github.com/flowup-labs/godynamo/godynamotest"
+	"github.com/stretchr/testify/suite"
+	"regexp"
+	"testing"
+	"time"
+)
+
+type IDSuite struct {
+	suite.Suite
+}
+
+func (t *IDSuite) TestUUIDGenerator() {
+	id, err := UUIDGenerator{}.NewID()
+	t.Nil(err)
+	t.Regexp(regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), id)
+}
+
+func (t *IDSuite) TestULIDGenerator() {
+	first, err := ULIDGenerator{}.NewID()
+	t.Nil(err)
+
+	time.Sleep(2 * time.Millisecond)
+
+	second, err := ULIDGenerator{}.NewID()
+	t.Nil(err)
+
+	t.Regexp(regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`), first)
+	t.True(first < second)
+}
+
+func (t *IDSuite) TestULIDGeneratorClock() {
+	clock := godynamotest.NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	first, err := ULIDGenerator{Clock: clock}.NewID()
+	t.Nil(err)
+	second, err := ULIDGenerator{Clock: clock}.NewID()
+	t.Nil(err)
+
+	// 1577836800000 ms
+	t.Equal("01DXF6DT00", first[:10])
+	t.Equal(first[:10], second[:10])
+}
+
+func (t *IDSuite) TestEncodeULID() {
+	t.Equal("00000000000000000000000000", encodeULID(make([]byte, 16)))
+
+	max := make([]byte, 16)
+	for i := range max {
+		max[i] = 0xFF
+	}
+	t.Equal("7ZZZZZZZZZZZZZZZZZZZZZZZZZ", encodeULID(max))
+}
+
+func (t *IDSuite) TestKSUIDGenerator() {
+	first, err := KSUIDGenerator{}.NewID()
+	t.Nil(err)
+
+	time.Sleep(1100 * time.Millisecond)
+
+	second, err := KSUIDGenerator{}.NewID()
+	t.Nil(err)
+
+	t.Regexp(regexp.MustCompile(`^[0-9A-Za-z]{27}$`), first)
+	t.True(first < second)
+}
+
+func (t *IDSuite) TestEncodeKSUID() {
+	t.Equal("000000000000000000000000000", encodeKSUID(make([]byte, 20)))
+
+	max := make([]byte, 20)
+	for i := range max {
+		max[i] = 0xFF
+	}
+	t.Equal("aWgEPTl1tmebfsQzFP4bxwgy80V", encodeKSUID(max))
+}
+
+func (t *IDSuite) TestSequenceGenerator() {
+	generator := &SequenceGenerator{Prefix: "user_"}
+
+	first, err := generator.NewID()
+	t.Nil(err)
+	second, err := generator.NewID()
+	t.Nil(err)
+
+	t.Equal("user_00000000000000000001", first)
+	t.Equal("user_00000000000000000002", second)
+}
+
+func (t *IDSuite) TestModelIDGenerator() {
+	access := NewDynamoAccess(defaults.Config(), "id_", WithIDGenerator(&SequenceGenerator{}))
+
+	id, err := access.modelConfig(&aaa{}).newID()
+	t.Nil(err)
+	t.Equal("00000000000000000001", id)
+}
+
+func TestIDSuite(t *testing.T) {
+	suite.Run(t, &IDSuite{})
+}
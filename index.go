@@ -0,0 +1,112 @@
+package godynamo
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// placeholders of attribute names in built expressions
+var namePlaceholder = regexp.MustCompile(`#[0-9A-Za-z_]+`)
+
+// SelectIndex, name of index which key matches the attributes of key condition, empty for the table,
+// the table is preferred, otherwise exactly one index must have hash among attributes
+// and no attribute outside of its hash and range
+func (s *TableSchema) SelectIndex(attributes ...string) (string, error) {
+	if len(attributes) == 0 {
+		return "", fmt.Errorf("%w of table %s: key condition has no attributes", ErrNoMatchingIndex, s.Table)
+	}
+
+	if s.Key.matches(attributes) {
+		return "", nil
+	}
+
+	var matching []string
+	for _, index := range append(append([]IndexSchema{}, s.GlobalIndexes...), s.LocalIndexes...) {
+		if index.Key.matches(attributes) {
+			matching = append(matching, index.Name)
+		}
+	}
+	sort.Strings(matching)
+
+	switch len(matching) {
+	case 0:
+		return "", fmt.Errorf("%w of table %s: %s", ErrNoMatchingIndex, s.Table, strings.Join(attributes, ", "))
+	case 1:
+		return matching[0], nil
+	}
+
+	return "", fmt.Errorf("%w of table %s: %s match %s", ErrAmbiguousIndex, s.Table, strings.Join(attributes, ", "), strings.Join(matching, ", "))
+}
+
+// SelectIndex, name of index the query of input is sent to, empty for the table,
+// index of input is kept, otherwise it is selected by attributes of key condition,
+// key condition on hash and range tagged on the model is sent to the table without building the schema
+func (a *DynamoAccess) SelectIndex(item interface{}, input RequestInput) (string, error) {
+	if input.IndexName != "" {
+		return input.IndexName, nil
+	}
+
+	attributes := keyConditionAttributes(input.Expr)
+	if tableKey(modelType(item)).matches(attributes) {
+		return "", nil
+	}
+
+	schema, err := a.Schema(item)
+	if err != nil {
+		return "", err
+	}
+
+	return schema.SelectIndex(attributes...)
+}
+
+// tableKey, hash and range attributes tagged on fields of the model
+func tableKey(t reflect.Type) KeySchema {
+	var key KeySchema
+	if t == nil {
+		return key
+	}
+
+	fields, _ := modelFields(t)
+	for _, field := range fields {
+		dynamoTag, ok := field.Tag.Lookup("godynamo")
+		switch {
+		case !ok:
+		case hasTagFunc(dynamoTag, "hash"):
+			key.Hash = field.attribute
+		case hasTagFunc(dynamoTag, "range"):
+			key.Range = field.attribute
+		}
+	}
+
+	return key
+}
+
+// matches, whether key condition on the attributes can be served by the key
+func (k KeySchema) matches(attributes []string) bool {
+	hash := false
+	for _, attribute := range attributes {
+		switch attribute {
+		case k.Hash:
+			hash = true
+		case k.Range:
+		default:
+			return false
+		}
+	}
+
+	return hash
+}
+
+// keyConditionAttributes, names of attributes used in key condition of expression
+func keyConditionAttributes(expr expression.Expression) []string {
+	keyCondition := expr.KeyCondition()
+	if keyCondition == nil {
+		return nil
+	}
+
+	return placeholderNames(*keyCondition, expr.Names())
+}
@@ -0,0 +1,129 @@
+package godynamo
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws/defaults"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type IndexSuite struct {
+	suite.Suite
+
+	access *DynamoAccess
+}
+
+func (t *IndexSuite) SetupTest() {
+	t.access = NewDynamoAccess(defaults.Config(), "x_")
+}
+
+func (t *IndexSuite) TestSelectIndex() {
+	schema, err := t.access.Schema(&eee{})
+	t.Nil(err)
+
+	candidates := []struct {
+		attributes []string
+		expected   string
+	}{
+		{[]string{"id"}, ""},
+		{[]string{"eea"}, "index"},
+		{[]string{"eeb", "eea"}, "index"},
+		{[]string{"eec", "eeb"}, "index2"},
+	}
+
+	for _, candidate := range candidates {
+		index, err := schema.SelectIndex(candidate.attributes...)
+		t.Nil(err)
+		t.Equal(candidate.expected, index)
+	}
+
+	schema, err = t.access.Schema(&fff{})
+	t.Nil(err)
+
+	index, err := schema.SelectIndex("id")
+	t.Nil(err)
+	t.Equal("", index)
+
+	index, err = schema.SelectIndex("id", "ffc")
+	t.Nil(err)
+	t.Equal("index", index)
+}
+
+func (t *IndexSuite) TestSelectIndexFails() {
+	schema := &TableSchema{
+		Table: "x_eee",
+		Key:   KeySchema{Hash: "id"},
+		GlobalIndexes: []IndexSchema{
+			{Name: "b", Key: KeySchema{Hash: "eea", Range: "eeb"}},
+			{Name: "a", Key: KeySchema{Hash: "eea", Range: "eec"}},
+		},
+	}
+
+	_, err := schema.SelectIndex("eea")
+	t.True(errors.Is(err, ErrAmbiguousIndex))
+	t.EqualError(err, "more indexes match key condition of table x_eee: eea match a, b")
+
+	_, err = schema.SelectIndex("eeb")
+	t.True(errors.Is(err, ErrNoMatchingIndex))
+	t.EqualError(err, "no index matches key condition of table x_eee: eeb")
+
+	_, err = schema.SelectIndex("eea", "eeb", "eec")
+	t.True(errors.Is(err, ErrNoMatchingIndex))
+
+	_, err = schema.SelectIndex()
+	t.True(errors.Is(err, ErrNoMatchingIndex))
+}
+
+func (t *IndexSuite) TestSelectIndexOfInput() {
+	keyCondition := expression.KeyAnd(expression.Key("eec").Equal(expression.Value(1)), expression.Key("eeb").GreaterThan(expression.Value(2)))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCondition).Build()
+	t.Nil(err)
+
+	index, err := t.access.SelectIndex(&[]eee{}, RequestInput{Expr: expr})
+	t.Nil(err)
+	t.Equal("index2", index)
+
+	index, err = t.access.SelectIndex(&[]eee{}, RequestInput{Expr: expr, IndexName: "index"})
+	t.Nil(err)
+	t.Equal("index", index)
+}
+
+func (t *IndexSuite) TestSelectIndexWithoutSchema() {
+	expr, err := expression.NewBuilder().WithKeyCondition(expression.Key("id").Equal(expression.Value("1"))).Build()
+	t.Nil(err)
+
+	// key of the table needs no schema
+	index, err := t.access.SelectIndex(&[]localIndexWithoutRange{}, RequestInput{Expr: expr})
+	t.Nil(err)
+	t.Equal("", index)
+
+	expr, err = expression.NewBuilder().WithKeyCondition(expression.Key("name").Equal(expression.Value("1"))).Build()
+	t.Nil(err)
+
+	_, err = t.access.SelectIndex(&[]localIndexWithoutRange{}, RequestInput{Expr: expr})
+	t.True(errors.Is(err, ErrValidation))
+	t.Equal(KeySchema{Hash: "id"}, tableKey(modelType(&[]localIndexWithoutRange{})))
+	t.Equal(KeySchema{Hash: "id", Range: "ffb"}, tableKey(modelType(&fff{})))
+}
+
+func (t *IndexSuite) TestQueryBuilderSelectsIndex() {
+	input, err := t.access.From(&[]user{}).Where("created_at", Eq, 1).And("first_name", BeginsWith, "J").Build()
+	t.Nil(err)
+	t.Equal("created_at_first_name_index", input.IndexName)
+
+	input, err = t.access.From(&[]fff{}).Where("id", Eq, "1").Build()
+	t.Nil(err)
+	t.Equal("", input.IndexName)
+
+	input, err = t.access.From(&[]fff{}).Where("id", Eq, "1").And("ffc", Gt, 1).Build()
+	t.Nil(err)
+	t.Equal("index", input.IndexName)
+
+	_, err = t.access.From(&[]user{}).Index("").Where("created_at", Eq, 1).Build()
+	t.EqualError(err, `invalid query of model user: "created_at" is not key attribute of table x_user (email HASH); missing condition on hash key "email"`)
+}
+
+func TestIndexSuite(t *testing.T) {
+	suite.Run(t, new(IndexSuite))
+}
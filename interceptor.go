@@ -0,0 +1,108 @@
+package godynamo
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"reflect"
+)
+
+// OperationName, kind of call of DynamoAccess
+type OperationName string
+
+const (
+	OperationCreateTable   OperationName = "create_table"
+	OperationDropTable     OperationName = "drop_table"
+	OperationCreate        OperationName = "create"
+	OperationUpdate        OperationName = "update"
+	OperationDelete        OperationName = "delete"
+	OperationSoftDelete    OperationName = "soft_delete"
+	OperationGetItem       OperationName = "get_item"
+	OperationGetItems      OperationName = "get_items"
+	OperationQuery         OperationName = "query"
+	OperationScan          OperationName = "scan"
+	OperationDumpTable     OperationName = "dump_table"
+	OperationBind          OperationName = "bind"
+	OperationPlanSchema    OperationName = "plan_schema"
+	OperationMigrateSchema OperationName = "migrate_schema"
+	OperationBatchGet      OperationName = "batch_get"
+
+	OperationExecuteStatement      OperationName = "execute_statement"
+	OperationBatchExecuteStatement OperationName = "batch_execute_statement"
+)
+
+// Operation, describes one call of DynamoAccess, interceptors may modify it before it is handled
+type Operation struct {
+	Name OperationName
+	// Table and Model, empty for batch get and batch execute statement, which use several tables
+	Table string
+	Model reflect.Type
+	// Item, *BatchGet of batch get, see its Keys, []BatchStatement of batch execute statement
+	Item interface{}
+
+	// Key, key of item of get item, delete and soft delete
+	Key map[string]dynamodb.AttributeValue
+	// Keys, keys of items of get items
+	Keys []map[string]dynamodb.AttributeValue
+	// Input, expression, index and paging of query and scan, index of query is selected by key condition when empty
+	Input RequestInput
+
+	// Result, *dynamodb.ScanOutput of scan, []byte of dump table, SchemaPlan of plan and migrate schema,
+	// next token of execute statement
+	Result interface{}
+	// Capacity, capacity consumed by the operation, when consumed capacity is collected
+	Capacity []CapacityUsage
+	// Retries, how many times requests of the operation were retried
+	Retries int
+	// Cached, items were read from cache
+	Cached bool
+}
+
+// Handler, executes operation
+type Handler func(op *Operation) error
+
+// Interceptor, wraps every operation, calls next to continue, or returns without it to short-circuit the call
+type Interceptor func(op *Operation, next Handler) error
+
+// WithInterceptors, adds interceptors to the chain, first one is the outermost
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(a *DynamoAccess) {
+		a.interceptors = append(a.interceptors, interceptors...)
+	}
+}
+
+// operation, describes call of given name on the table of item
+func (a *DynamoAccess) operation(name OperationName, item interface{}) (*Operation, bool, error) {
+	tableName, slice, err := a.tableName(item)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &Operation{
+		Name:  name,
+		Table: *tableName,
+		Model: modelType(item),
+		Item:  item,
+	}, slice, nil
+}
+
+// intercept, runs handler wrapped by all interceptors
+func (a *DynamoAccess) intercept(op *Operation, handler Handler) error {
+	handler = translated(handler)
+	for i := len(a.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := a.interceptors[i], handler
+		handler = func(op *Operation) error {
+			return interceptor(op, next)
+		}
+	}
+
+	return handler(op)
+}
+
+// modelType, struct type of item, pointers and slices are dereferenced
+func modelType(item interface{}) reflect.Type {
+	t := reflect.TypeOf(item)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+
+	return t
+}
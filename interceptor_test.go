@@ -0,0 +1,82 @@
+package godynamo
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/defaults"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/suite"
+	"reflect"
+	"testing"
+)
+
+type InterceptorSuite struct {
+	suite.Suite
+}
+
+func (t *InterceptorSuite) TestChainOrder() {
+	var calls []string
+	record := func(name string) Interceptor {
+		return func(op *Operation, next Handler) error {
+			calls = append(calls, name+" before")
+			err := next(op)
+			calls = append(calls, name+" after")
+			return err
+		}
+	}
+
+	access := NewDynamoAccess(defaults.Config(), "x_", WithInterceptors(record("first"), record("second")))
+
+	err := access.intercept(&Operation{}, func(op *Operation) error {
+		calls = append(calls, "handler")
+		return nil
+	})
+	t.Nil(err)
+	t.Equal([]string{"first before", "second before", "handler", "second after", "first after"}, calls)
+}
+
+func (t *InterceptorSuite) TestShortCircuit() {
+	var ops []Operation
+	errDenied := errors.New("denied")
+
+	access := NewDynamoAccess(defaults.Config(), "x_", WithInterceptors(func(op *Operation, next Handler) error {
+		ops = append(ops, *op)
+
+		switch op.Name {
+		case OperationScan:
+			op.Result = &dynamodb.ScanOutput{Count: aws.Int64(0)}
+			return nil
+		case OperationDumpTable:
+			op.Result = []byte("[]")
+			return nil
+		}
+
+		return errDenied
+	}))
+
+	result, err := access.Scan(&[]aaa{}, RequestInput{})
+	t.Nil(err)
+	t.Equal(int64(0), *result.Count)
+
+	data, err := access.DumpTable(&aaa{})
+	t.Nil(err)
+	t.Equal([]byte("[]"), data)
+
+	t.Equal(errDenied, access.GetItem(&aaa{}, "id", "1"))
+	t.Equal(errDenied, access.Create(&aaa{}))
+	t.Equal([]error{errDenied}, access.DropTables(&aaa{}))
+
+	t.Equal(OperationScan, ops[0].Name)
+	t.Equal("x_aaa", ops[0].Table)
+	t.Equal(reflect.TypeOf(aaa{}), ops[0].Model)
+
+	t.Equal(OperationGetItem, ops[2].Name)
+	t.Equal(map[string]dynamodb.AttributeValue{"id": {S: aws.String("1")}}, ops[2].Key)
+
+	t.Equal(OperationCreate, ops[3].Name)
+	t.Equal(OperationDropTable, ops[4].Name)
+}
+
+func TestInterceptorSuite(t *testing.T) {
+	suite.Run(t, new(InterceptorSuite))
+}
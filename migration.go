@@ -1,32 +1,72 @@
 package godynamo
 
 import (
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"encoding/json"
-	"os"
-	"io/ioutil"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/dynamodbattribute"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
 )
 
+// DumpTable, items of first page of scan of the table as json, the scan is guarded by scan policy
 func (a *DynamoAccess) DumpTable(table interface{}) ([]byte, error) {
 
-	tableName, _, err := a.tableName(table)
+	op, _, err := a.operation(OperationDumpTable, table)
 	if err != nil {
 		return []byte{}, err
 	}
 
+	reservation, err := a.guardScan(op)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	var result *dynamodb.ScanOutput
+	if err := a.intercept(op, func(op *Operation) (err error) {
+		result, err = a.dumpTable(op)
+		return err
+	}); err != nil {
+		a.settleScan(reservation, nil)
+		return []byte{}, err
+	}
+	a.settleScan(reservation, result)
+
+	data, _ := op.Result.([]byte)
+	return data, nil
+}
+
+func (a *DynamoAccess) dumpTable(op *Operation) (*dynamodb.ScanOutput, error) {
 	scanInput := &dynamodb.ScanInput{
-		TableName: tableName,
+		TableName:              aws.String(op.Table),
+		ReturnConsumedCapacity: a.returnConsumedCapacity(),
+	}
+	if op.Input.Limit > 0 {
+		scanInput.Limit = aws.Int64(op.Input.Limit)
 	}
-	result, err := a.svc.ScanRequest(scanInput).Send()
+	var result *dynamodb.ScanOutput
+	if err := a.send(op, func() (err error) {
+		result, err = a.svc.ScanRequest(scanInput).Send()
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	a.consumed(op, result.ConsumedCapacity)
+
+	data, err := json.Marshal(result.Items)
 	if err != nil {
-		return []byte{}, err
+		return nil, err
 	}
 
-	return json.Marshal(result.Items)
+	op.Result = data
+	return result, nil
 }
 
-func (a *DynamoAccess) WriteStringToFile(data string, path string) (error) {
+func (a *DynamoAccess) WriteStringToFile(data string, path string) error {
 	file, err := os.Create(path)
 	if err != nil {
 		return err
@@ -50,15 +90,667 @@ func (a *DynamoAccess) OpenFile(path string) ([]byte, error) {
 }
 
 func (a *DynamoAccess) Bind(item interface{}, data []byte) error {
-	attributeValues := []map[string]dynamodb.AttributeValue{}
-
-	if err := json.Unmarshal(data, &attributeValues); err != nil {
+	op, _, err := a.operation(OperationBind, item)
+	if err != nil {
 		return err
 	}
 
-	if err := dynamodbattribute.UnmarshalListOfMaps(attributeValues, item); err != nil {
+	return a.intercept(op, func(op *Operation) error {
+		attributeValues := []map[string]dynamodb.AttributeValue{}
+
+		if err := json.Unmarshal(data, &attributeValues); err != nil {
+			return err
+		}
+
+		if err := dynamodbattribute.UnmarshalListOfMaps(attributeValues, op.Item); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// SchemaChangeType, kind of change needed to migrate table to the schema declared by tags
+type SchemaChangeType string
+
+const (
+	SchemaChangeCreateTable     SchemaChangeType = "create_table"
+	SchemaChangeAddIndex        SchemaChangeType = "add_index"
+	SchemaChangeRemoveIndex     SchemaChangeType = "remove_index"
+	SchemaChangeThroughput      SchemaChangeType = "throughput"
+	SchemaChangeIndexThroughput SchemaChangeType = "index_throughput"
+	SchemaChangeTimeToLive      SchemaChangeType = "ttl"
+	SchemaChangeStream          SchemaChangeType = "stream"
+	SchemaChangeBackup          SchemaChangeType = "point_in_time_recovery"
+	SchemaChangeTags            SchemaChangeType = "tags"
+	SchemaChangeUnsupported     SchemaChangeType = "unsupported"
+)
+
+// how often is table polled, when waiting on finish of update
+var schemaPollInterval = 5 * time.Second
+
+// how long is table waited on, when access has no schema timeout
+var defaultSchemaTimeout = time.Hour
+
+// WithSchemaTimeout, how long CreateTables and MigrateSchema wait until table and its indexes are active,
+// before they fail by ErrTableNotActive, one hour by default, measured by clock of access
+func WithSchemaTimeout(timeout time.Duration) Option {
+	return func(a *DynamoAccess) {
+		a.schemaTimeout = timeout
+	}
+}
+
+// SchemaChange, one step of the table migration
+type SchemaChange struct {
+	Type        SchemaChangeType
+	Index       string
+	Description string
+
+	apply func(a *DynamoAccess, tableName *string) error
+}
+
+func (c SchemaChange) String() string {
+	switch c.Type {
+	case SchemaChangeCreateTable, SchemaChangeAddIndex:
+		return "+ " + c.Description
+	case SchemaChangeRemoveIndex:
+		return "- " + c.Description
+	case SchemaChangeUnsupported:
+		return "! " + c.Description
+	}
+
+	return "~ " + c.Description
+}
+
+// SchemaPlan, list of changes which migrates table to the schema declared by tags of model
+type SchemaPlan struct {
+	Table   string
+	Changes []SchemaChange
+}
+
+// Empty, table already matches the model
+func (p SchemaPlan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Supported, all changes of the plan can be applied in place
+func (p SchemaPlan) Supported() bool {
+	for _, change := range p.Changes {
+		if change.Type == SchemaChangeUnsupported {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p SchemaPlan) String() string {
+	if p.Empty() {
+		return fmt.Sprintf("table %s: up to date", p.Table)
+	}
+
+	lines := []string{fmt.Sprintf("table %s:", p.Table)}
+	for _, change := range p.Changes {
+		lines = append(lines, "  "+change.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// PlanSchema, compares schema declared by tags of given models with the tables in db
+func (a *DynamoAccess) PlanSchema(items ...interface{}) ([]SchemaPlan, error) {
+	plans := make([]SchemaPlan, 0, len(items))
+
+	for _, item := range items {
+		op, _, err := a.operation(OperationPlanSchema, item)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := a.intercept(op, a.planSchema); err != nil {
+			return nil, err
+		}
+
+		plan, _ := op.Result.(SchemaPlan)
+		plans = append(plans, plan)
+	}
+
+	return plans, nil
+}
+
+// MigrateSchema, applies changes of schema declared by tags of given models on the tables in db,
+// global secondary indexes are created one at a time, and each is waited for until backfill is done,
+// point in time recovery and resource tags are only enabled and added, never removed
+func (a *DynamoAccess) MigrateSchema(items ...interface{}) ([]SchemaPlan, error) {
+	plans, err := a.PlanSchema(items...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, plan := range plans {
+		if !plan.Supported() {
+			return plans, fmt.Errorf("%w\n%s", ErrUnsupportedSchemaChange, plan)
+		}
+	}
+
+	for i, plan := range plans {
+		op, _, err := a.operation(OperationMigrateSchema, items[i])
+		if err != nil {
+			return plans, err
+		}
+		op.Table = plan.Table
+		op.Result = plan
+
+		if err := a.intercept(op, a.migrateSchema); err != nil {
+			return plans, err
+		}
+	}
+
+	return plans, nil
+}
+
+// migrateSchema, applies changes of plan in result of operation
+func (a *DynamoAccess) migrateSchema(op *Operation) error {
+	plan, _ := op.Result.(SchemaPlan)
+
+	for _, change := range plan.Changes {
+		if err := change.apply(a, aws.String(op.Table)); err != nil {
+			return err
+		}
+
+		if err := a.waitForTable(aws.String(op.Table)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *DynamoAccess) planSchema(op *Operation) error {
+	plan, err := a.tableSchemaPlan(op.Item, op.Table)
+	if err != nil {
 		return err
 	}
 
+	op.Result = plan
 	return nil
 }
+
+func (a *DynamoAccess) tableSchemaPlan(item interface{}, tableName string) (SchemaPlan, error) {
+	expected, err := a.tableInput(item)
+	if err != nil {
+		return SchemaPlan{}, err
+	}
+	expected.TableName = aws.String(tableName)
+
+	plan := SchemaPlan{Table: *expected.TableName}
+
+	ttl, err := a.ttlAttribute(item)
+	if err != nil {
+		return SchemaPlan{}, err
+	}
+
+	result, err := a.svc.DescribeTableRequest(&dynamodb.DescribeTableInput{
+		TableName: expected.TableName,
+	}).Send()
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == dynamodb.ErrCodeResourceNotFoundException {
+			plan.Changes = append(plan.Changes, SchemaChange{
+				Type:        SchemaChangeCreateTable,
+				Description: fmt.Sprintf("create table (%s)", keySchemaString(expected.KeySchema)),
+				apply: func(a *DynamoAccess, tableName *string) error {
+					result, err := a.svc.CreateTableRequest(expected).Send()
+					if err != nil {
+						return err
+					}
+					return a.configureTable(item, result.TableDescription)
+				},
+			})
+			return plan, nil
+		}
+		return SchemaPlan{}, err
+	}
+	actual := result.Table
+
+	if !keySchemaEqual(expected.KeySchema, actual.KeySchema) {
+		plan.Changes = append(plan.Changes, SchemaChange{
+			Type: SchemaChangeUnsupported,
+			Description: fmt.Sprintf("change key schema (%s) -> (%s)",
+				keySchemaString(actual.KeySchema), keySchemaString(expected.KeySchema)),
+		})
+	}
+
+	if !localIndexesEqual(expected.LocalSecondaryIndexes, actual.LocalSecondaryIndexes) {
+		plan.Changes = append(plan.Changes, SchemaChange{
+			Type:        SchemaChangeUnsupported,
+			Description: "change local secondary indexes",
+		})
+	}
+
+	plan.Changes = append(plan.Changes, globalIndexChanges(expected, actual)...)
+
+	if change, ok := throughputChange(expected.ProvisionedThroughput, actual.ProvisionedThroughput); ok {
+		plan.Changes = append(plan.Changes, SchemaChange{
+			Type:        SchemaChangeThroughput,
+			Description: "update table throughput " + change,
+			apply: func(a *DynamoAccess, tableName *string) error {
+				_, err := a.svc.UpdateTableRequest(&dynamodb.UpdateTableInput{
+					TableName:             tableName,
+					ProvisionedThroughput: expected.ProvisionedThroughput,
+				}).Send()
+				return err
+			},
+		})
+	}
+
+	ttlResult, err := a.svc.DescribeTimeToLiveRequest(&dynamodb.DescribeTimeToLiveInput{
+		TableName: expected.TableName,
+	}).Send()
+	if err != nil {
+		return SchemaPlan{}, err
+	}
+
+	plan.Changes = append(plan.Changes, timeToLiveChanges(ttlResult.TimeToLiveDescription, ttl)...)
+
+	settingsChanges, err := a.settingsChanges(a.settings(item), actual)
+	if err != nil {
+		return SchemaPlan{}, err
+	}
+
+	plan.Changes = append(plan.Changes, settingsChanges...)
+
+	return plan, nil
+}
+
+// settingsChanges, changes of stream, encryption, point in time recovery and tags of the table
+func (a *DynamoAccess) settingsChanges(settings TableSettings, actual *dynamodb.TableDescription) ([]SchemaChange, error) {
+	var changes []SchemaChange
+
+	var actualStream dynamodb.StreamViewType
+	if actual.StreamSpecification != nil && aws.BoolValue(actual.StreamSpecification.StreamEnabled) {
+		actualStream = actual.StreamSpecification.StreamViewType
+	}
+
+	if actualStream != settings.Stream {
+		// view type of enabled stream can not be changed
+		if actualStream != "" {
+			changes = append(changes, streamChange(actualStream, &dynamodb.StreamSpecification{
+				StreamEnabled: aws.Bool(false),
+			}))
+		}
+		if settings.Stream != "" {
+			changes = append(changes, streamChange(settings.Stream, settings.streamSpecification()))
+		}
+	}
+
+	actualEncryption := actual.SSEDescription != nil &&
+		(actual.SSEDescription.Status == dynamodb.SSEStatusEnabled || actual.SSEDescription.Status == dynamodb.SSEStatusEnabling)
+	if actualEncryption != settings.Encryption {
+		changes = append(changes, SchemaChange{
+			Type:        SchemaChangeUnsupported,
+			Description: fmt.Sprintf("change server-side encryption %t -> %t", actualEncryption, settings.Encryption),
+		})
+	}
+
+	if settings.PointInTimeRecovery {
+		result, err := a.svc.DescribeContinuousBackupsRequest(&dynamodb.DescribeContinuousBackupsInput{
+			TableName: actual.TableName,
+		}).Send()
+		if err != nil {
+			return nil, err
+		}
+
+		if description := result.ContinuousBackupsDescription; description == nil ||
+			description.PointInTimeRecoveryDescription == nil ||
+			description.PointInTimeRecoveryDescription.PointInTimeRecoveryStatus != dynamodb.PointInTimeRecoveryStatusEnabled {
+			changes = append(changes, SchemaChange{
+				Type:        SchemaChangeBackup,
+				Description: "enable point in time recovery",
+				apply: func(a *DynamoAccess, tableName *string) error {
+					return a.applyPointInTimeRecovery(tableName, true)
+				},
+			})
+		}
+	}
+
+	if len(settings.Tags) > 0 {
+		actualTags := map[string]string{}
+		input := &dynamodb.ListTagsOfResourceInput{ResourceArn: actual.TableArn}
+		for {
+			result, err := a.svc.ListTagsOfResourceRequest(input).Send()
+			if err != nil {
+				return nil, err
+			}
+
+			for _, tag := range result.Tags {
+				actualTags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+
+			if result.NextToken == nil {
+				break
+			}
+			input.NextToken = result.NextToken
+		}
+
+		var tags []dynamodb.Tag
+		var descriptions []string
+		for _, tag := range settings.tags() {
+			if value, ok := actualTags[*tag.Key]; !ok || value != *tag.Value {
+				tags = append(tags, tag)
+				descriptions = append(descriptions, fmt.Sprintf("%s=%s", *tag.Key, *tag.Value))
+			}
+		}
+
+		if len(tags) > 0 {
+			tableArn := actual.TableArn
+			changes = append(changes, SchemaChange{
+				Type:        SchemaChangeTags,
+				Description: "tag table " + strings.Join(descriptions, ", "),
+				apply: func(a *DynamoAccess, tableName *string) error {
+					return a.applyTags(tableArn, tags)
+				},
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+func streamChange(viewType dynamodb.StreamViewType, specification *dynamodb.StreamSpecification) SchemaChange {
+	description := fmt.Sprintf("enable stream %s", viewType)
+	if !aws.BoolValue(specification.StreamEnabled) {
+		description = fmt.Sprintf("disable stream %s", viewType)
+	}
+
+	return SchemaChange{
+		Type:        SchemaChangeStream,
+		Description: description,
+		apply: func(a *DynamoAccess, tableName *string) error {
+			_, err := a.svc.UpdateTableRequest(&dynamodb.UpdateTableInput{
+				TableName:           tableName,
+				StreamSpecification: specification,
+			}).Send()
+			return err
+		},
+	}
+}
+
+// timeToLiveChanges, enables or disables ttl of the table, db rejects another update of ttl until the previous one
+// is done, which may take up to an hour, so change of ttl attribute, or change while ttl is enabling or disabling,
+// is unsupported
+func timeToLiveChanges(description *dynamodb.TimeToLiveDescription, ttl string) []SchemaChange {
+	status, actual := dynamodb.TimeToLiveStatusDisabled, ""
+	if description != nil && description.TimeToLiveStatus != "" {
+		status, actual = description.TimeToLiveStatus, aws.StringValue(description.AttributeName)
+	}
+
+	switch status {
+	case dynamodb.TimeToLiveStatusEnabled, dynamodb.TimeToLiveStatusEnabling:
+		if actual == ttl {
+			return nil
+		}
+		change := "disable ttl on attribute " + actual
+		if ttl != "" {
+			change = fmt.Sprintf("change ttl attribute %s -> %s", actual, ttl)
+		}
+		if status == dynamodb.TimeToLiveStatusEnabling {
+			return []SchemaChange{{Type: SchemaChangeUnsupported, Description: change + ", ttl is still enabling"}}
+		}
+		if ttl != "" {
+			return []SchemaChange{{Type: SchemaChangeUnsupported, Description: change + ", ttl has to be disabled first"}}
+		}
+		return []SchemaChange{timeToLiveChange(actual, false)}
+	case dynamodb.TimeToLiveStatusDisabling:
+		if ttl == "" {
+			return nil
+		}
+		return []SchemaChange{{
+			Type:        SchemaChangeUnsupported,
+			Description: fmt.Sprintf("enable ttl on attribute %s, ttl on attribute %s is still disabling", ttl, actual),
+		}}
+	}
+
+	if ttl == "" {
+		return nil
+	}
+
+	return []SchemaChange{timeToLiveChange(ttl, true)}
+}
+
+func timeToLiveChange(attribute string, enabled bool) SchemaChange {
+	description := "enable ttl on attribute " + attribute
+	if !enabled {
+		description = "disable ttl on attribute " + attribute
+	}
+
+	return SchemaChange{
+		Type:        SchemaChangeTimeToLive,
+		Description: description,
+		apply: func(a *DynamoAccess, tableName *string) error {
+			return a.applyTimeToLive(tableName, attribute, enabled)
+		},
+	}
+}
+
+// globalIndexChanges, removed indexes goes first to free the attributes and limit of indexes,
+// changed indexes are recreated
+func globalIndexChanges(expected *dynamodb.CreateTableInput, actual *dynamodb.TableDescription) []SchemaChange {
+	var removes, adds, updates []SchemaChange
+
+	for _, actualIndex := range actual.GlobalSecondaryIndexes {
+		expectedIndex, ok := findGlobalIndex(expected.GlobalSecondaryIndexes, *actualIndex.IndexName)
+		if ok && keySchemaEqual(expectedIndex.KeySchema, actualIndex.KeySchema) &&
+			projectionEqual(expectedIndex.Projection, actualIndex.Projection) {
+			continue
+		}
+
+		indexName := actualIndex.IndexName
+		removes = append(removes, SchemaChange{
+			Type:        SchemaChangeRemoveIndex,
+			Index:       *indexName,
+			Description: fmt.Sprintf("remove global secondary index %s (%s)", *indexName, keySchemaString(actualIndex.KeySchema)),
+			apply: func(a *DynamoAccess, tableName *string) error {
+				_, err := a.svc.UpdateTableRequest(&dynamodb.UpdateTableInput{
+					TableName: tableName,
+					GlobalSecondaryIndexUpdates: []dynamodb.GlobalSecondaryIndexUpdate{
+						{Delete: &dynamodb.DeleteGlobalSecondaryIndexAction{IndexName: indexName}},
+					},
+				}).Send()
+				return err
+			},
+		})
+	}
+
+	for _, expectedIndex := range expected.GlobalSecondaryIndexes {
+		index := expectedIndex
+
+		actualIndex, ok := findGlobalIndexDescription(actual.GlobalSecondaryIndexes, *index.IndexName)
+		if ok && keySchemaEqual(index.KeySchema, actualIndex.KeySchema) &&
+			projectionEqual(index.Projection, actualIndex.Projection) {
+			if change, ok := throughputChange(index.ProvisionedThroughput, actualIndex.ProvisionedThroughput); ok {
+				updates = append(updates, SchemaChange{
+					Type:        SchemaChangeIndexThroughput,
+					Index:       *index.IndexName,
+					Description: fmt.Sprintf("update throughput of global secondary index %s %s", *index.IndexName, change),
+					apply: func(a *DynamoAccess, tableName *string) error {
+						_, err := a.svc.UpdateTableRequest(&dynamodb.UpdateTableInput{
+							TableName: tableName,
+							GlobalSecondaryIndexUpdates: []dynamodb.GlobalSecondaryIndexUpdate{
+								{Update: &dynamodb.UpdateGlobalSecondaryIndexAction{
+									IndexName:             index.IndexName,
+									ProvisionedThroughput: index.ProvisionedThroughput,
+								}},
+							},
+						}).Send()
+						return err
+					},
+				})
+			}
+			continue
+		}
+
+		adds = append(adds, SchemaChange{
+			Type:        SchemaChangeAddIndex,
+			Index:       *index.IndexName,
+			Description: fmt.Sprintf("add global secondary index %s (%s)", *index.IndexName, keySchemaString(index.KeySchema)),
+			apply: func(a *DynamoAccess, tableName *string) error {
+				_, err := a.svc.UpdateTableRequest(&dynamodb.UpdateTableInput{
+					TableName:            tableName,
+					AttributeDefinitions: keyAttributeDefinitions(expected.AttributeDefinitions, index.KeySchema),
+					GlobalSecondaryIndexUpdates: []dynamodb.GlobalSecondaryIndexUpdate{
+						{Create: &dynamodb.CreateGlobalSecondaryIndexAction{
+							IndexName:             index.IndexName,
+							KeySchema:             index.KeySchema,
+							Projection:            index.Projection,
+							ProvisionedThroughput: index.ProvisionedThroughput,
+						}},
+					},
+				}).Send()
+				return err
+			},
+		})
+	}
+
+	return append(append(removes, adds...), updates...)
+}
+
+// waitForTable, blocks until table and all its global secondary indexes are active and backfilled,
+// fails by ErrTableNotActive when schema timeout runs out
+func (a *DynamoAccess) waitForTable(tableName *string) error {
+	timeout := a.schemaTimeout
+	if timeout <= 0 {
+		timeout = defaultSchemaTimeout
+	}
+	deadline := now(a.clock).Add(timeout)
+
+	for {
+		result, err := a.svc.DescribeTableRequest(&dynamodb.DescribeTableInput{
+			TableName: tableName,
+		}).Send()
+		if err != nil {
+			return err
+		}
+
+		ready := result.Table.TableStatus == dynamodb.TableStatusActive
+		for _, index := range result.Table.GlobalSecondaryIndexes {
+			if index.IndexStatus != dynamodb.IndexStatusActive || aws.BoolValue(index.Backfilling) {
+				ready = false
+			}
+		}
+
+		if ready {
+			return nil
+		}
+
+		if now(a.clock).Add(schemaPollInterval).After(deadline) {
+			return fmt.Errorf("%w: table %s or its indexes after %s", ErrTableNotActive, aws.StringValue(tableName), timeout)
+		}
+
+		time.Sleep(schemaPollInterval)
+	}
+}
+
+func findGlobalIndex(indexes []dynamodb.GlobalSecondaryIndex, name string) (dynamodb.GlobalSecondaryIndex, bool) {
+	for _, index := range indexes {
+		if *index.IndexName == name {
+			return index, true
+		}
+	}
+
+	return dynamodb.GlobalSecondaryIndex{}, false
+}
+
+func findGlobalIndexDescription(indexes []dynamodb.GlobalSecondaryIndexDescription, name string) (dynamodb.GlobalSecondaryIndexDescription, bool) {
+	for _, index := range indexes {
+		if *index.IndexName == name {
+			return index, true
+		}
+	}
+
+	return dynamodb.GlobalSecondaryIndexDescription{}, false
+}
+
+func localIndexesEqual(expected []dynamodb.LocalSecondaryIndex, actual []dynamodb.LocalSecondaryIndexDescription) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+
+	for _, expectedIndex := range expected {
+		found := false
+		for _, actualIndex := range actual {
+			if *expectedIndex.IndexName == *actualIndex.IndexName &&
+				keySchemaEqual(expectedIndex.KeySchema, actualIndex.KeySchema) {
+				found = true
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func keySchemaEqual(expected, actual []dynamodb.KeySchemaElement) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+
+	for i := range expected {
+		if *expected[i].AttributeName != *actual[i].AttributeName || expected[i].KeyType != actual[i].KeyType {
+			return false
+		}
+	}
+
+	return true
+}
+
+func projectionEqual(expected, actual *dynamodb.Projection) bool {
+	if expected == nil || actual == nil {
+		return expected == actual
+	}
+
+	return expected.ProjectionType == actual.ProjectionType
+}
+
+// throughputChange, returns readable difference of throughput, if there is any
+func throughputChange(expected *dynamodb.ProvisionedThroughput, actual *dynamodb.ProvisionedThroughputDescription) (string, bool) {
+	if expected == nil || actual == nil {
+		return "", false
+	}
+
+	if aws.Int64Value(expected.ReadCapacityUnits) == aws.Int64Value(actual.ReadCapacityUnits) &&
+		aws.Int64Value(expected.WriteCapacityUnits) == aws.Int64Value(actual.WriteCapacityUnits) {
+		return "", false
+	}
+
+	return fmt.Sprintf("read %d -> %d, write %d -> %d",
+		aws.Int64Value(actual.ReadCapacityUnits), aws.Int64Value(expected.ReadCapacityUnits),
+		aws.Int64Value(actual.WriteCapacityUnits), aws.Int64Value(expected.WriteCapacityUnits),
+	), true
+}
+
+// keyAttributeDefinitions, definitions of the attributes used in the key schema
+func keyAttributeDefinitions(definitions []dynamodb.AttributeDefinition, keySchema []dynamodb.KeySchemaElement) []dynamodb.AttributeDefinition {
+	var result []dynamodb.AttributeDefinition
+
+	for _, definition := range definitions {
+		for _, key := range keySchema {
+			if *definition.AttributeName == *key.AttributeName {
+				result = append(result, definition)
+			}
+		}
+	}
+
+	return result
+}
+
+func keySchemaString(keySchema []dynamodb.KeySchemaElement) string {
+	keys := make([]string, 0, len(keySchema))
+	for _, key := range keySchema {
+		keys = append(keys, fmt.Sprintf("%s %s", *key.AttributeName, key.KeyType))
+	}
+
+	return strings.Join(keys, ", ")
+}
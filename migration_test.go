@@ -1,10 +1,10 @@
 package godynamo
 
 import (
-	"github.com/stretchr/testify/suite"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/defaults"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/suite"
 	"testing"
 )
 
@@ -22,7 +22,7 @@ func (t *MigrationSuite) SetupSuite() {
 	config.Credentials = aws.StaticCredentialsProvider{
 		Value: aws.Credentials{
 			AccessKeyID: "AKID", SecretAccessKey: "SECRET", SessionToken: "SESSION",
-			Source:      "unit test credentials",
+			Source: "unit test credentials",
 		},
 	}
 
@@ -32,9 +32,9 @@ func (t *MigrationSuite) SetupSuite() {
 
 func (t *MigrationSuite) SetupTest() {
 
-	t.access.DropTables(&aaa{}, &ccc{}, &bbb{}, &ddd{}, &fff{}, &eee{}, &user{})
+	t.access.DropTables(&aaa{}, &ccc{}, &bbb{}, &ddd{}, &fff{}, &eee{}, &user{}, &ggg{}, &hhh{}, &iii{}, &kkk{}, &lll{}, &mmm{})
 
-	t.access.CreateTables(&aaa{}, &ccc{}, &bbb{}, &ddd{}, &fff{}, &eee{}, &user{})
+	t.access.CreateTables(&aaa{}, &ccc{}, &bbb{}, &ddd{}, &fff{}, &eee{}, &user{}, &ggg{}, &hhh{}, &iii{}, &kkk{}, &lll{}, &mmm{})
 }
 
 func (t *MigrationSuite) TestMigration() {
@@ -76,6 +76,54 @@ func (t *MigrationSuite) TestMigration() {
 
 }
 
+func (t *MigrationSuite) TestMigrateSchema() {
+	t.access.DropTables(&user{})
+
+	// table created before the index was declared
+	_, err := t.svc.CreateTableRequest(&dynamodb.CreateTableInput{
+		TableName: aws.String(t.access.tablePrefix + "user"),
+		AttributeDefinitions: []dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("email"),
+				AttributeType: dynamodb.ScalarAttributeTypeS,
+			},
+		},
+		KeySchema: []dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("email"),
+				KeyType:       dynamodb.KeyTypeHash,
+			},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(5),
+		},
+	}).Send()
+	t.Nil(err)
+
+	plans, err := t.access.PlanSchema(&user{})
+	t.Nil(err)
+	t.Len(plans, 1)
+	t.Len(plans[0].Changes, 2)
+	t.Equal(SchemaChangeAddIndex, plans[0].Changes[0].Type)
+	t.Equal("created_at_first_name_index", plans[0].Changes[0].Index)
+	t.Equal(SchemaChangeThroughput, plans[0].Changes[1].Type)
+
+	_, err = t.access.MigrateSchema(&user{})
+	t.Nil(err)
+
+	result, err := t.svc.DescribeTableRequest(&dynamodb.DescribeTableInput{
+		TableName: aws.String(t.access.tablePrefix + "user"),
+	}).Send()
+	t.Nil(err)
+	t.Len(result.Table.GlobalSecondaryIndexes, 1)
+	t.Equal(int64(10), *result.Table.ProvisionedThroughput.ReadCapacityUnits)
+
+	plans, err = t.access.PlanSchema(&user{})
+	t.Nil(err)
+	t.True(plans[0].Empty())
+}
+
 func TestMigrationSuite(t *testing.T) {
 	suite.Run(t, &MigrationSuite{})
 }
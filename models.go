@@ -1,8 +1,10 @@
 package godynamo
 
 import (
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
+	"errors"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
+	"strings"
 )
 
 // Represents basic model data as id, and
@@ -137,11 +139,11 @@ type eee struct {
 }
 
 type user struct {
-	FirstName string `json:"first_name" godynamo:"global_secondary_index(created_at_first_name_index:range)"`
-	LastName  string `json:"last_name"`
-	Email     string `json:"email" godynamo:"hash"`
-	CreatedAt int64  `json:"created_at" godynamo:"global_secondary_index(created_at_first_name_index:hash)"`
-	UpdatedAt int64  `json:"updated_at"`
+	FirstName            string `json:"first_name" godynamo:"global_secondary_index(created_at_first_name_index:range)"`
+	LastName             string `json:"last_name"`
+	Email                string `json:"email" godynamo:"hash"`
+	CreatedAt            int64  `json:"created_at" godynamo:"global_secondary_index(created_at_first_name_index:hash)"`
+	UpdatedAt            int64  `json:"updated_at"`
 	AuthenticationTokens []struct {
 		Token      string `json:"token"`
 		LastUsedAt string `json:"last_used_at"`
@@ -152,3 +154,101 @@ type user struct {
 		Country string `json:"country"`
 	} `json:"addresses"`
 }
+
+type ggg struct {
+	Model
+
+	Ga      string `json:"gga"`
+	Expires int64  `json:"expires,omitempty" godynamo:"ttl"`
+}
+
+type hhh struct {
+	Model
+
+	Ha string `json:"hha"`
+}
+
+func (h *hhh) TableSettings(defaults TableSettings) TableSettings {
+	defaults.Stream = dynamodb.StreamViewTypeNewAndOldImages
+	defaults.Tags["team"] = "core"
+	return defaults
+}
+
+type iii struct {
+	Model
+
+	Ia string `dynamodbav:"iia" json:"ia" godynamo:"global_secondary_index(index:hash)"`
+	Ib int64  `dynamodbav:",omitempty" json:"iib" godynamo:"global_secondary_index(index:range)"`
+	Ic string `json:"iic,omitempty"`
+}
+
+type kkk struct {
+	Key       string `json:"key" godynamo:"hash"`
+	Value     string `json:"value"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func (k *kkk) ModelConfig(defaults ModelConfig) ModelConfig {
+	defaults.CreatedAttribute = "created_at"
+	defaults.UpdatedAttribute = "updated_at"
+	defaults.TimestampFormat = TimestampRFC3339
+	return defaults
+}
+
+type mmm struct {
+	Model
+
+	Ma      string `json:"mma"`
+	Removed int64  `json:"removed"`
+}
+
+func (m *mmm) ModelConfig(defaults ModelConfig) ModelConfig {
+	defaults.DeletedAttribute = "removed"
+	return defaults
+}
+
+var errEmptyLa = errors.New("lla is empty")
+
+type lll struct {
+	Model
+
+	La    string   `json:"lla"`
+	Hooks []string `json:"-"`
+}
+
+func (l *lll) BeforeCreate() error {
+	l.La = strings.TrimSpace(l.La)
+	l.Hooks = append(l.Hooks, "before_create")
+	return nil
+}
+
+func (l *lll) AfterCreate() error {
+	l.Hooks = append(l.Hooks, "after_create")
+	return nil
+}
+
+func (l *lll) BeforeUpdate() error {
+	l.La = strings.TrimSpace(l.La)
+	l.Hooks = append(l.Hooks, "before_update")
+	return nil
+}
+
+func (l *lll) BeforeDelete() error {
+	l.Hooks = append(l.Hooks, "before_delete")
+	return nil
+}
+
+func (l *lll) AfterLoad() error {
+	l.Hooks = append(l.Hooks, "after_load")
+	return nil
+}
+
+func (l *lll) Validate() error {
+	if l.La == "" {
+		return errEmptyLa
+	}
+
+	l.Hooks = append(l.Hooks, "validate")
+	return nil
+}
@@ -0,0 +1,314 @@
+package godynamo
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/dynamodbattribute"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maximal number of statements of one BatchExecuteStatement request
+const batchStatementLimit = 25
+
+// requests of PartiQL, which the pinned sdk does not have, they are sent by target of DynamoDB_20120810
+const (
+	opExecuteStatement      = "ExecuteStatement"
+	opBatchExecuteStatement = "BatchExecuteStatement"
+)
+
+// Statement, PartiQL statement of model, name of the model after FROM, INTO or UPDATE is replaced by its table,
+// e.g. SELECT * FROM user WHERE email = ?, parameters are bound to ? in order
+type Statement struct {
+	Statement      string
+	Parameters     []interface{}
+	ConsistentRead bool
+	// NextToken, continues reading of ExecuteStatement after page, which returned it
+	NextToken string
+}
+
+// BatchStatement, statement of BatchExecuteStatement, items it returns are unmarshaled into slice Item
+type BatchStatement struct {
+	Item interface{}
+	Statement
+}
+
+// executeStatementInput, input of ExecuteStatement request
+type executeStatementInput struct {
+	_ struct{} `type:"structure"`
+
+	Statement              *string                         `type:"string" required:"true"`
+	Parameters             []dynamodb.AttributeValue       `type:"list"`
+	ConsistentRead         *bool                           `type:"boolean"`
+	NextToken              *string                         `type:"string"`
+	ReturnConsumedCapacity dynamodb.ReturnConsumedCapacity `type:"string" enum:"true"`
+}
+
+// executeStatementOutput, output of ExecuteStatement request
+type executeStatementOutput struct {
+	_ struct{} `type:"structure"`
+
+	Items            []map[string]dynamodb.AttributeValue `type:"list"`
+	NextToken        *string                              `type:"string"`
+	ConsumedCapacity *dynamodb.ConsumedCapacity           `type:"structure"`
+}
+
+// batchStatementRequest, one statement of BatchExecuteStatement request
+type batchStatementRequest struct {
+	_ struct{} `type:"structure"`
+
+	Statement      *string                   `type:"string" required:"true"`
+	Parameters     []dynamodb.AttributeValue `type:"list"`
+	ConsistentRead *bool                     `type:"boolean"`
+}
+
+// batchExecuteStatementInput, input of BatchExecuteStatement request
+type batchExecuteStatementInput struct {
+	_ struct{} `type:"structure"`
+
+	Statements             []batchStatementRequest         `type:"list" required:"true"`
+	ReturnConsumedCapacity dynamodb.ReturnConsumedCapacity `type:"string" enum:"true"`
+}
+
+// batchStatementResponse, result of one statement of BatchExecuteStatement, in order of statements
+type batchStatementResponse struct {
+	_ struct{} `type:"structure"`
+
+	Error     *batchStatementFailure             `type:"structure"`
+	Item      map[string]dynamodb.AttributeValue `type:"map"`
+	TableName *string                            `type:"string"`
+}
+
+// batchExecuteStatementOutput, output of BatchExecuteStatement request
+type batchExecuteStatementOutput struct {
+	_ struct{} `type:"structure"`
+
+	Responses        []batchStatementResponse    `type:"list"`
+	ConsumedCapacity []dynamodb.ConsumedCapacity `type:"list"`
+}
+
+// batchStatementFailure, error of one statement of BatchExecuteStatement
+type batchStatementFailure struct {
+	_ struct{} `type:"structure"`
+
+	Code    *string `type:"string" enum:"true"`
+	Message *string `type:"string"`
+}
+
+// StatementFailure, why statement of batch failed, e.g. ConditionalCheckFailed
+type StatementFailure struct {
+	Code    string
+	Message string
+}
+
+// BatchStatementError, statements of BatchExecuteStatement which failed, by their index,
+// the other statements are applied and their items are unmarshaled
+type BatchStatementError struct {
+	Failures map[int]StatementFailure
+}
+
+// Is, batch statement error is error of failed statement
+func (e *BatchStatementError) Is(target error) bool {
+	return target == ErrStatementFailed
+}
+
+func (e *BatchStatementError) Error() string {
+	indexes := make([]int, 0, len(e.Failures))
+	for index := range e.Failures {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	failures := make([]string, 0, len(indexes))
+	for _, index := range indexes {
+		failure := e.Failures[index]
+		failures = append(failures, fmt.Sprintf("%d (%s: %s)", index, failure.Code, failure.Message))
+	}
+
+	return fmt.Sprintf("%s: %s", ErrStatementFailed, strings.Join(failures, ", "))
+}
+
+// ExecuteStatement, runs PartiQL statement on the table of model of slice item, returned items are unmarshaled into it,
+// returns token of the next page, empty after the last one, see Statement.NextToken
+func (a *DynamoAccess) ExecuteStatement(item interface{}, statement Statement) (string, error) {
+	op, slice, err := a.operation(OperationExecuteStatement, item)
+	if err != nil {
+		return "", err
+	}
+	if !slice {
+		return "", ErrNotSlice
+	}
+
+	if err := a.intercept(op, func(op *Operation) error {
+		return a.executeStatement(op, statement)
+	}); err != nil {
+		return "", err
+	}
+
+	token, _ := op.Result.(string)
+	return token, nil
+}
+
+func (a *DynamoAccess) executeStatement(op *Operation, statement Statement) error {
+	parameters, err := statementParameters(statement.Parameters)
+	if err != nil {
+		return err
+	}
+
+	input := &executeStatementInput{
+		Statement:              aws.String(a.prefixedStatement(op.Model, statement.Statement)),
+		Parameters:             parameters,
+		ReturnConsumedCapacity: a.returnConsumedCapacity(),
+	}
+	if statement.ConsistentRead {
+		input.ConsistentRead = aws.Bool(true)
+	}
+	if statement.NextToken != "" {
+		input.NextToken = aws.String(statement.NextToken)
+	}
+
+	var result *executeStatementOutput
+	if err := a.send(op, func() error {
+		result = &executeStatementOutput{}
+		return a.svc.NewRequest(&aws.Operation{
+			Name:       opExecuteStatement,
+			HTTPMethod: "POST",
+			HTTPPath:   "/",
+		}, input, result).Send()
+	}); err != nil {
+		return err
+	}
+	a.consumed(op, result.ConsumedCapacity)
+
+	if err := a.loadItems(op, result.Items); err != nil {
+		return err
+	}
+
+	op.Result = aws.StringValue(result.NextToken)
+	return nil
+}
+
+// BatchExecuteStatement, runs statements by requests of at most 25 statements, items returned by them are unmarshaled
+// into their slices, statements sharing slice fill it together, statements which failed are returned by *BatchStatementError
+func (a *DynamoAccess) BatchExecuteStatement(statements ...BatchStatement) error {
+	ops := make([]*Operation, 0, len(statements))
+	for _, statement := range statements {
+		op, slice, err := a.operation(OperationBatchExecuteStatement, statement.Item)
+		if err != nil {
+			return err
+		}
+		if !slice {
+			return ErrNotSlice
+		}
+		ops = append(ops, op)
+	}
+
+	return a.intercept(&Operation{Name: OperationBatchExecuteStatement, Item: statements}, func(op *Operation) error {
+		return a.batchExecuteStatement(op, statements, ops)
+	})
+}
+
+func (a *DynamoAccess) batchExecuteStatement(op *Operation, statements []BatchStatement, ops []*Operation) error {
+	requests := make([]batchStatementRequest, 0, len(statements))
+	for i, statement := range statements {
+		parameters, err := statementParameters(statement.Parameters)
+		if err != nil {
+			return err
+		}
+
+		request := batchStatementRequest{
+			Statement:  aws.String(a.prefixedStatement(ops[i].Model, statement.Statement.Statement)),
+			Parameters: parameters,
+		}
+		if statement.ConsistentRead {
+			request.ConsistentRead = aws.Bool(true)
+		}
+		requests = append(requests, request)
+	}
+
+	items := make([][]map[string]dynamodb.AttributeValue, len(statements))
+	failed := &BatchStatementError{Failures: map[int]StatementFailure{}}
+	for start := 0; start < len(requests); start += batchStatementLimit {
+		end := start + batchStatementLimit
+		if end > len(requests) {
+			end = len(requests)
+		}
+
+		var result *batchExecuteStatementOutput
+		if err := a.send(op, func() error {
+			result = &batchExecuteStatementOutput{}
+			return a.svc.NewRequest(&aws.Operation{
+				Name:       opBatchExecuteStatement,
+				HTTPMethod: "POST",
+				HTTPPath:   "/",
+			}, &batchExecuteStatementInput{
+				Statements:             requests[start:end],
+				ReturnConsumedCapacity: a.returnConsumedCapacity(),
+			}, result).Send()
+		}); err != nil {
+			return err
+		}
+		for i := range result.ConsumedCapacity {
+			a.consumed(op, &result.ConsumedCapacity[i])
+		}
+
+		// responses are in order of statements
+		for i, response := range result.Responses {
+			switch {
+			case response.Error != nil:
+				failed.Failures[start+i] = StatementFailure{
+					Code:    aws.StringValue(response.Error.Code),
+					Message: aws.StringValue(response.Error.Message),
+				}
+			case len(response.Item) > 0:
+				items[start+i] = append(items[start+i], response.Item)
+			}
+		}
+	}
+
+	// items of statements into the same slice are loaded together
+	loaded := map[interface{}]bool{}
+	for i, statementOp := range ops {
+		if loaded[statementOp.Item] {
+			continue
+		}
+		loaded[statementOp.Item] = true
+
+		var found []map[string]dynamodb.AttributeValue
+		for j := i; j < len(ops); j++ {
+			if ops[j].Item == statementOp.Item {
+				found = append(found, items[j]...)
+			}
+		}
+		if err := a.loadItems(statementOp, found); err != nil {
+			return err
+		}
+	}
+
+	if len(failed.Failures) > 0 {
+		return failed
+	}
+
+	return nil
+}
+
+// prefixedStatement, statement with name of the model after FROM, INTO or UPDATE replaced by quoted name of its table
+func (a *DynamoAccess) prefixedStatement(model reflect.Type, statement string) string {
+	name := regexp.QuoteMeta(model.Name())
+	pattern := regexp.MustCompile(`(?i)\b(FROM|INTO|UPDATE)(\s+)("` + name + `"|` + name + `\b)`)
+	table := strings.Replace(a.tablePrefix+model.Name(), "$", "$$", -1)
+
+	return pattern.ReplaceAllString(statement, `${1}${2}"`+table+`"`)
+}
+
+// statementParameters, attribute values of parameters bound to ? of statement
+func statementParameters(values []interface{}) ([]dynamodb.AttributeValue, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	return dynamodbattribute.MarshalList(values)
+}
@@ -0,0 +1,61 @@
+package godynamo
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws/defaults"
+	"github.com/stretchr/testify/suite"
+	"reflect"
+	"testing"
+)
+
+type PartiQLSuite struct {
+	suite.Suite
+
+	access *DynamoAccess
+}
+
+func (t *PartiQLSuite) SetupTest() {
+	t.access = NewDynamoAccess(defaults.Config(), "x_")
+}
+
+func (t *PartiQLSuite) TestPrefixedStatement() {
+	model := reflect.TypeOf(user{})
+
+	candidates := map[string]string{
+		`SELECT * FROM user WHERE email = ?`:                 `SELECT * FROM "x_user" WHERE email = ?`,
+		`select * from "user"."created_at_first_name_index"`: `select * from "x_user"."created_at_first_name_index"`,
+		`INSERT INTO user VALUE {'email': ?}`:                `INSERT INTO "x_user" VALUE {'email': ?}`,
+		`UPDATE user SET first_name = ? WHERE email = ?`:     `UPDATE "x_user" SET first_name = ? WHERE email = ?`,
+		`DELETE FROM "user" WHERE email = ?`:                 `DELETE FROM "x_user" WHERE email = ?`,
+		`SELECT * FROM user_log WHERE user = ?`:              `SELECT * FROM user_log WHERE user = ?`,
+		`SELECT * FROM x_user WHERE email = ?`:               `SELECT * FROM x_user WHERE email = ?`,
+	}
+
+	for statement, expected := range candidates {
+		t.Equal(expected, t.access.prefixedStatement(model, statement))
+	}
+
+	access := NewDynamoAccess(defaults.Config(), "$1_")
+	t.Equal(`SELECT * FROM "$1_user"`, access.prefixedStatement(model, `SELECT * FROM user`))
+}
+
+func (t *PartiQLSuite) TestNotSlice() {
+	_, err := t.access.ExecuteStatement(&user{}, Statement{Statement: "SELECT * FROM user"})
+	t.Equal(ErrNotSlice, err)
+
+	t.Equal(ErrNotSlice, t.access.BatchExecuteStatement(BatchStatement{Item: &user{}}))
+}
+
+func (t *PartiQLSuite) TestBatchStatementError() {
+	err := &BatchStatementError{Failures: map[int]StatementFailure{
+		3: {Code: "DuplicateItem", Message: "duplicate primary key"},
+		0: {Code: "ConditionalCheckFailed", Message: "condition failed"},
+	}}
+
+	t.True(errors.Is(err, ErrStatementFailed))
+	t.EqualError(err, "statement failed: 0 (ConditionalCheckFailed: condition failed), 3 (DuplicateItem: duplicate primary key)")
+}
+
+func TestPartiQLSuite(t *testing.T) {
+	suite.Run(t, new(PartiQLSuite))
+}
@@ -0,0 +1,311 @@
+package godynamo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
+	"reflect"
+	"strings"
+)
+
+// Operator, comparison of attribute in key condition or filter
+type Operator string
+
+const (
+	Eq         Operator = "="
+	Ne         Operator = "<>"
+	Lt         Operator = "<"
+	Le         Operator = "<="
+	Gt         Operator = ">"
+	Ge         Operator = ">="
+	Between    Operator = "BETWEEN"
+	BeginsWith Operator = "begins_with"
+	Contains   Operator = "contains"
+)
+
+// operators allowed on range key in key condition
+var rangeOperators = map[Operator]bool{Eq: true, Lt: true, Le: true, Gt: true, Ge: true, Between: true, BeginsWith: true}
+
+type condition struct {
+	name     string
+	operator Operator
+	values   []interface{}
+}
+
+// QueryBuilder, chainable query of model, validated against its schema when built
+type QueryBuilder struct {
+	access     *DynamoAccess
+	item       interface{}
+	index      string
+	indexSet   bool
+	conditions []condition
+	filters    []condition
+	desc       bool
+	limit      int64
+	startKey   map[string]dynamodb.AttributeValue
+}
+
+// From, starts query, which unmarshals found items into given item or slice
+func (a *DynamoAccess) From(item interface{}) *QueryBuilder {
+	return &QueryBuilder{access: a, item: item}
+}
+
+// Index, queries global or local secondary index, the table when name is empty,
+// without it the table or index is selected by attributes of key conditions
+func (q *QueryBuilder) Index(name string) *QueryBuilder {
+	q.index, q.indexSet = name, true
+	return q
+}
+
+// Where, adds key condition on hash or range key of the table or index
+func (q *QueryBuilder) Where(name string, operator Operator, values ...interface{}) *QueryBuilder {
+	q.conditions = append(q.conditions, condition{name: name, operator: operator, values: values})
+	return q
+}
+
+// And, adds next key condition, same as Where
+func (q *QueryBuilder) And(name string, operator Operator, values ...interface{}) *QueryBuilder {
+	return q.Where(name, operator, values...)
+}
+
+// Filter, adds condition on attribute, which is not part of the key, applied after items are read
+func (q *QueryBuilder) Filter(name string, operator Operator, values ...interface{}) *QueryBuilder {
+	q.filters = append(q.filters, condition{name: name, operator: operator, values: values})
+	return q
+}
+
+// Desc, items are returned in descending order of range key
+func (q *QueryBuilder) Desc() *QueryBuilder {
+	q.desc = true
+	return q
+}
+
+// Limit, maximal number of items read
+func (q *QueryBuilder) Limit(limit int64) *QueryBuilder {
+	q.limit = limit
+	return q
+}
+
+// StartFrom, continues query after given key, see LastEvaluatedKey
+func (q *QueryBuilder) StartFrom(key map[string]dynamodb.AttributeValue) *QueryBuilder {
+	q.startKey = key
+	return q
+}
+
+// All, runs query, found items are unmarshaled into the item of From
+func (q *QueryBuilder) All() error {
+	input, err := q.Build()
+	if err != nil {
+		return err
+	}
+
+	return q.access.Query(q.item, input)
+}
+
+// Build, validates query against schema of model and builds its request input
+func (q *QueryBuilder) Build() (RequestInput, error) {
+	schema, err := q.access.Schema(q.item)
+	if err != nil {
+		return RequestInput{}, err
+	}
+
+	var problems []string
+
+	if !q.indexSet {
+		names := make([]string, 0, len(q.conditions))
+		for _, c := range q.conditions {
+			names = append(names, c.name)
+		}
+
+		// without matching index the table is queried, so its key is validated
+		index, err := schema.SelectIndex(names...)
+		if errors.Is(err, ErrAmbiguousIndex) {
+			problems = append(problems, err.Error())
+		}
+		q.index = index
+	}
+
+	key, ok := schema.indexKey(q.index)
+	if !ok {
+		problems = append(problems, fmt.Sprintf("unknown index %q", q.index))
+	}
+
+	attributes := map[string]bool{}
+	fields, _ := modelFields(reflect.TypeOf(q.item))
+	for _, field := range fields {
+		attributes[field.attribute] = true
+	}
+
+	var hash, rangeKey *condition
+	for i, c := range q.conditions {
+		if problem := c.problem(attributes); problem != "" {
+			problems = append(problems, problem)
+			continue
+		}
+
+		switch {
+		case !ok:
+		case c.name == key.Hash:
+			if hash != nil {
+				problems = append(problems, fmt.Sprintf("duplicate condition on hash key %q", c.name))
+			} else if c.operator != Eq {
+				problems = append(problems, fmt.Sprintf("hash key %q can be compared by %s only", c.name, Eq))
+			}
+			hash = &q.conditions[i]
+		case c.name == key.Range:
+			if rangeKey != nil {
+				problems = append(problems, fmt.Sprintf("duplicate condition on range key %q", c.name))
+			} else if !rangeOperators[c.operator] {
+				problems = append(problems, fmt.Sprintf("range key %q can not be compared by %s", c.name, c.operator))
+			}
+			rangeKey = &q.conditions[i]
+		default:
+			problems = append(problems, fmt.Sprintf("%q is not key attribute of %s (%s)", c.name, schema.indexDescription(q.index), key))
+		}
+	}
+
+	if ok && hash == nil {
+		problems = append(problems, fmt.Sprintf("missing condition on hash key %q", key.Hash))
+	}
+
+	for _, c := range q.filters {
+		if problem := c.problem(attributes); problem != "" {
+			problems = append(problems, problem)
+		} else if c.name == key.Hash || c.name == key.Range {
+			problems = append(problems, fmt.Sprintf("key attribute %q can not be filtered, use Where", c.name))
+		}
+	}
+
+	if q.limit < 0 {
+		problems = append(problems, fmt.Sprintf("negative limit %d", q.limit))
+	}
+
+	if len(problems) > 0 {
+		return RequestInput{}, fmt.Errorf("%w of model %s: %s", ErrInvalidQuery, modelType(q.item).Name(), strings.Join(problems, "; "))
+	}
+
+	keyCondition := hash.keyCondition()
+	if rangeKey != nil {
+		keyCondition = keyCondition.And(rangeKey.keyCondition())
+	}
+
+	builder := expression.NewBuilder().WithKeyCondition(keyCondition)
+	if len(q.filters) > 0 {
+		filter := q.filters[0].filter()
+		for _, c := range q.filters[1:] {
+			filter = filter.And(c.filter())
+		}
+		builder = builder.WithFilter(filter)
+	}
+
+	expr, err := builder.Build()
+	if err != nil {
+		return RequestInput{}, err
+	}
+
+	return RequestInput{
+		Expr:              expr,
+		IndexName:         q.index,
+		Limit:             q.limit,
+		ScanIndexForward:  !q.desc,
+		ExclusiveStartKey: q.startKey,
+	}, nil
+}
+
+// problem, describes what is wrong with attribute or values of condition, empty when nothing
+func (c condition) problem(attributes map[string]bool) string {
+	if !attributes[c.name] {
+		return fmt.Sprintf("unknown attribute %q", c.name)
+	}
+
+	expected := 1
+	switch c.operator {
+	case Eq, Ne, Lt, Le, Gt, Ge:
+	case Between:
+		expected = 2
+	case BeginsWith, Contains:
+		if len(c.values) == 1 {
+			if _, ok := c.values[0].(string); !ok {
+				return fmt.Sprintf("%s of %q needs string value", c.operator, c.name)
+			}
+		}
+	default:
+		return fmt.Sprintf("unknown operator %q of %q", c.operator, c.name)
+	}
+
+	if len(c.values) != expected {
+		return fmt.Sprintf("%s of %q needs %d values, got %d", c.operator, c.name, expected, len(c.values))
+	}
+
+	return ""
+}
+
+func (c condition) keyCondition() expression.KeyConditionBuilder {
+	key := expression.Key(c.name)
+
+	switch c.operator {
+	case Lt:
+		return key.LessThan(expression.Value(c.values[0]))
+	case Le:
+		return key.LessThanEqual(expression.Value(c.values[0]))
+	case Gt:
+		return key.GreaterThan(expression.Value(c.values[0]))
+	case Ge:
+		return key.GreaterThanEqual(expression.Value(c.values[0]))
+	case Between:
+		return key.Between(expression.Value(c.values[0]), expression.Value(c.values[1]))
+	case BeginsWith:
+		return key.BeginsWith(c.values[0].(string))
+	}
+
+	return key.Equal(expression.Value(c.values[0]))
+}
+
+func (c condition) filter() expression.ConditionBuilder {
+	name := expression.Name(c.name)
+
+	switch c.operator {
+	case Ne:
+		return name.NotEqual(expression.Value(c.values[0]))
+	case Lt:
+		return name.LessThan(expression.Value(c.values[0]))
+	case Le:
+		return name.LessThanEqual(expression.Value(c.values[0]))
+	case Gt:
+		return name.GreaterThan(expression.Value(c.values[0]))
+	case Ge:
+		return name.GreaterThanEqual(expression.Value(c.values[0]))
+	case Between:
+		return name.Between(expression.Value(c.values[0]), expression.Value(c.values[1]))
+	case BeginsWith:
+		return name.BeginsWith(c.values[0].(string))
+	case Contains:
+		return name.Contains(c.values[0].(string))
+	}
+
+	return name.Equal(expression.Value(c.values[0]))
+}
+
+// indexKey, key schema of the index, of the table when name is empty
+func (s *TableSchema) indexKey(name string) (KeySchema, bool) {
+	if name == "" {
+		return s.Key, true
+	}
+
+	for _, index := range append(append([]IndexSchema{}, s.GlobalIndexes...), s.LocalIndexes...) {
+		if index.Name == name {
+			return index.Key, true
+		}
+	}
+
+	return KeySchema{}, false
+}
+
+func (s *TableSchema) indexDescription(name string) string {
+	if name == "" {
+		return "table " + s.Table
+	}
+
+	return "index " + name
+}
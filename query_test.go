@@ -0,0 +1,91 @@
+package godynamo
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws/defaults"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type QueryBuilderSuite struct {
+	suite.Suite
+
+	access *DynamoAccess
+}
+
+func (t *QueryBuilderSuite) SetupTest() {
+	t.access = NewDynamoAccess(defaults.Config(), "x_")
+}
+
+func (t *QueryBuilderSuite) TestBuild() {
+	input, err := t.access.From(&[]user{}).
+		Index("created_at_first_name_index").
+		Where("created_at", Eq, 1).
+		And("first_name", BeginsWith, "J").
+		Filter("last_name", Ne, "Doe").
+		Desc().
+		Limit(20).
+		Build()
+	t.Nil(err)
+	t.Equal("created_at_first_name_index", input.IndexName)
+	t.Equal(int64(20), input.Limit)
+	t.False(input.ScanIndexForward)
+
+	input, err = t.access.From(&[]fff{}).Where("id", Eq, "1").And("ffb", Between, "a", "b").Build()
+	t.Nil(err)
+	t.Equal("", input.IndexName)
+	t.True(input.ScanIndexForward)
+
+	_, err = t.access.From(&[]fff{}).Index("index").Where("id", Eq, "1").And("ffc", Gt, 1).Build()
+	t.Nil(err)
+}
+
+func (t *QueryBuilderSuite) TestInvalid() {
+	candidates := []struct {
+		query    *QueryBuilder
+		expected string
+	}{
+		{
+			t.access.From(&[]user{}).Index("missing").Where("email", Eq, "a"),
+			`invalid query of model user: unknown index "missing"`,
+		},
+		{
+			t.access.From(&[]user{}).Where("mail", Eq, "a"),
+			`invalid query of model user: unknown attribute "mail"; missing condition on hash key "email"`,
+		},
+		{
+			t.access.From(&[]user{}).Where("email", Gt, "a"),
+			`invalid query of model user: hash key "email" can be compared by = only`,
+		},
+		{
+			t.access.From(&[]user{}).Index("created_at_first_name_index").Where("created_at", Eq, 1).And("email", Eq, "a"),
+			`invalid query of model user: "email" is not key attribute of index created_at_first_name_index (created_at HASH, first_name RANGE)`,
+		},
+		{
+			t.access.From(&[]user{}).Index("created_at_first_name_index").Where("created_at", Eq, 1).And("first_name", Ne, "J"),
+			`invalid query of model user: range key "first_name" can not be compared by <>`,
+		},
+		{
+			t.access.From(&[]fff{}).Where("id", Eq, "1").And("ffb", Between, "a"),
+			`invalid query of model fff: BETWEEN of "ffb" needs 2 values, got 1`,
+		},
+		{
+			t.access.From(&[]fff{}).Where("id", Eq, "1").And("ffb", BeginsWith, 1),
+			`invalid query of model fff: begins_with of "ffb" needs string value`,
+		},
+		{
+			t.access.From(&[]fff{}).Where("id", Eq, "1").Filter("ffb", Eq, "a").Limit(-1),
+			`invalid query of model fff: key attribute "ffb" can not be filtered, use Where; negative limit -1`,
+		},
+	}
+
+	for _, candidate := range candidates {
+		_, err := candidate.query.Build()
+		t.EqualError(err, candidate.expected)
+		t.True(errors.Is(err, ErrInvalidQuery))
+	}
+}
+
+func TestQueryBuilderSuite(t *testing.T) {
+	suite.Run(t, new(QueryBuilderSuite))
+}
@@ -0,0 +1,183 @@
+package godynamo
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimit, read and write capacity units per second, zero is unlimited
+type RateLimit struct {
+	Read  float64
+	Write float64
+}
+
+// RateLimiter, token buckets of capacity units per table and index, safe for concurrent use,
+// request waits while bucket is empty, and consumed capacity of its response is taken from the bucket,
+// so the bucket may go into debt, which is paid by waiting of next requests
+type RateLimiter struct {
+	mutex   sync.Mutex
+	clock   Clock
+	buckets map[bucketKey]*bucket
+}
+
+type capacityMode int
+
+const (
+	capacityNone capacityMode = iota
+	capacityRead
+	capacityWrite
+)
+
+type bucketKey struct {
+	table string
+	index string
+	mode  capacityMode
+}
+
+type bucket struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// waits until bucket is refilled
+var rateLimitSleep = time.Sleep
+
+// NewRateLimiter, limiter measuring time by clock, system time when clock is nil
+func NewRateLimiter(clock Clock) *RateLimiter {
+	return &RateLimiter{clock: clock, buckets: map[bucketKey]*bucket{}}
+}
+
+// Limit, limits capacity consumed on the table, e.g. 30% of its provisioned throughput
+func (l *RateLimiter) Limit(table string, limit RateLimit) *RateLimiter {
+	return l.LimitIndex(table, "", limit)
+}
+
+// LimitIndex, limits capacity consumed on the index of table, requests on the index wait on its bucket instead of the table one
+func (l *RateLimiter) LimitIndex(table, index string, limit RateLimit) *RateLimiter {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	timeNow := now(l.clock)
+	for mode, rate := range map[capacityMode]float64{capacityRead: limit.Read, capacityWrite: limit.Write} {
+		key := bucketKey{table: table, index: index, mode: mode}
+		if rate <= 0 {
+			delete(l.buckets, key)
+			continue
+		}
+		l.buckets[key] = &bucket{rate: rate, tokens: rate, last: timeNow}
+	}
+
+	return l
+}
+
+// WithRateLimiter, requests are limited by given limiter
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(a *DynamoAccess) {
+		a.rateLimiter = limiter
+	}
+}
+
+// Limited, copy of access, which requests are limited by given limiter, e.g. for background jobs
+func (a *DynamoAccess) Limited(limiter *RateLimiter) *DynamoAccess {
+	limited := *a
+	limited.rateLimiter = limiter
+	return &limited
+}
+
+// capacityModeOf, whether operation consumes read or write capacity
+func capacityModeOf(name OperationName) capacityMode {
+	switch name {
+	case OperationCreate, OperationUpdate, OperationDelete, OperationSoftDelete:
+		return capacityWrite
+	case OperationGetItem, OperationGetItems, OperationQuery, OperationScan, OperationDumpTable, OperationBatchGet:
+		return capacityRead
+	}
+
+	return capacityNone
+}
+
+// wait, blocks until bucket of the operation has capacity
+func (l *RateLimiter) wait(op *Operation) {
+	if l == nil {
+		return
+	}
+
+	mode := capacityModeOf(op.Name)
+	if mode == capacityNone {
+		return
+	}
+
+	for {
+		l.mutex.Lock()
+		delay := time.Duration(0)
+		if b := l.bucket(op.Table, op.Input.IndexName, mode); b != nil {
+			delay = b.delay(now(l.clock))
+		}
+		l.mutex.Unlock()
+
+		if delay <= 0 {
+			return
+		}
+
+		rateLimitSleep(delay)
+	}
+}
+
+// consume, takes consumed capacity from buckets of tables and indexes
+func (l *RateLimiter) consume(op *Operation, usage []CapacityUsage) {
+	if l == nil {
+		return
+	}
+
+	mode := capacityModeOf(op.Name)
+	if mode == capacityNone {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	timeNow := now(l.clock)
+	for _, u := range usage {
+		// writes of indexes are taken only from buckets of limited indexes, not to count them twice on the table
+		b := l.buckets[bucketKey{table: u.Table, index: u.Index, mode: mode}]
+		if mode == capacityRead {
+			b = l.bucket(u.Table, u.Index, mode)
+		}
+
+		if b != nil {
+			b.refill(timeNow)
+			b.tokens -= u.Units
+		}
+	}
+}
+
+// bucket, bucket of index, or of its table when index is not limited
+func (l *RateLimiter) bucket(table, index string, mode capacityMode) *bucket {
+	if b := l.buckets[bucketKey{table: table, index: index, mode: mode}]; b != nil {
+		return b
+	}
+
+	return l.buckets[bucketKey{table: table, mode: mode}]
+}
+
+func (b *bucket) refill(timeNow time.Time) {
+	if elapsed := timeNow.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.rate {
+			b.tokens = b.rate
+		}
+		b.last = timeNow
+	}
+}
+
+// delay, how long to wait until bucket has capacity
+func (b *bucket) delay(timeNow time.Time) time.Duration {
+	b.refill(timeNow)
+	if b.tokens > 0 {
+		return 0
+	}
+
+	return time.Duration((-b.tokens/b.rate)*float64(time.Second)) + time.Millisecond
+}
@@ -0,0 +1,97 @@
+package godynamo
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws/defaults"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/flowup-labs/godynamo/godynamotest"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type RateLimitSuite struct {
+	suite.Suite
+
+	clock  *godynamotest.Clock
+	waited time.Duration
+}
+
+func (t *RateLimitSuite) SetupTest() {
+	t.clock = godynamotest.NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	t.waited = 0
+	rateLimitSleep = func(d time.Duration) {
+		t.waited += d
+		t.clock.Advance(d)
+	}
+}
+
+func (t *RateLimitSuite) TearDownTest() {
+	rateLimitSleep = time.Sleep
+}
+
+func (t *RateLimitSuite) TestWaitForCapacity() {
+	limiter := NewRateLimiter(t.clock).Limit("x_aaa", RateLimit{Read: 10, Write: 5})
+
+	read := &Operation{Name: OperationQuery, Table: "x_aaa"}
+	limiter.wait(read)
+	t.Equal(time.Duration(0), t.waited)
+
+	// read of 30 units puts bucket of 10 units per second 20 units into debt
+	limiter.consume(read, []CapacityUsage{{CapacityKey{OperationQuery, "x_aaa", ""}, 30}})
+	limiter.wait(read)
+	t.InDelta((2 * time.Second).Seconds(), t.waited.Seconds(), 0.01)
+
+	// writes have their own bucket
+	t.waited = 0
+	limiter.wait(&Operation{Name: OperationCreate, Table: "x_aaa"})
+	t.Equal(time.Duration(0), t.waited)
+
+	// other tables and table operations are not limited
+	limiter.consume(read, []CapacityUsage{{CapacityKey{OperationQuery, "x_aaa", ""}, 30}})
+	limiter.wait(&Operation{Name: OperationQuery, Table: "x_bbb"})
+	limiter.wait(&Operation{Name: OperationCreateTable, Table: "x_aaa"})
+	t.Equal(time.Duration(0), t.waited)
+}
+
+func (t *RateLimitSuite) TestIndexBuckets() {
+	limiter := NewRateLimiter(t.clock).
+		Limit("x_ddd", RateLimit{Read: 10, Write: 10}).
+		LimitIndex("x_ddd", "index1", RateLimit{Read: 1})
+
+	index := &Operation{Name: OperationQuery, Table: "x_ddd", Input: RequestInput{IndexName: "index1"}}
+	limiter.consume(index, []CapacityUsage{{CapacityKey{OperationQuery, "x_ddd", "index1"}, 2}})
+
+	// table bucket is untouched
+	limiter.wait(&Operation{Name: OperationQuery, Table: "x_ddd"})
+	t.Equal(time.Duration(0), t.waited)
+
+	limiter.wait(index)
+	t.InDelta((time.Second).Seconds(), t.waited.Seconds(), 0.01)
+
+	// reads of index, which is not limited, are taken from the table bucket
+	t.waited = 0
+	other := &Operation{Name: OperationQuery, Table: "x_ddd", Input: RequestInput{IndexName: "index2"}}
+	limiter.consume(other, []CapacityUsage{{CapacityKey{OperationQuery, "x_ddd", "index2"}, 20}})
+	limiter.wait(other)
+	t.InDelta((time.Second).Seconds(), t.waited.Seconds(), 0.01)
+
+	// writes of index, which is not limited, are not counted on the table
+	t.waited = 0
+	write := &Operation{Name: OperationCreate, Table: "x_ddd"}
+	limiter.consume(write, []CapacityUsage{{CapacityKey{OperationCreate, "x_ddd", "index2"}, 100}})
+	limiter.wait(write)
+	t.Equal(time.Duration(0), t.waited)
+}
+
+func (t *RateLimitSuite) TestLimited() {
+	access := NewDynamoAccess(defaults.Config(), "x_")
+	t.Equal(dynamodb.ReturnConsumedCapacity(""), access.returnConsumedCapacity())
+
+	limited := access.Limited(NewRateLimiter(t.clock).Limit("x_aaa", RateLimit{Read: 1}))
+	t.Nil(access.rateLimiter)
+	t.Equal(dynamodb.ReturnConsumedCapacityIndexes, limited.returnConsumedCapacity())
+}
+
+func TestRateLimitSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitSuite))
+}
@@ -0,0 +1,124 @@
+package godynamo
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy, how failed requests and unprocessed items of batch requests are retried
+type RetryPolicy struct {
+	// MaxAttempts, attempts of one request including the first one, request is not retried when less than 2
+	MaxAttempts int
+	// BaseDelay, delay before the first retry, doubled with every next one
+	BaseDelay time.Duration
+	// MaxDelay, upper limit of the delay
+	MaxDelay time.Duration
+	// Jitter, fraction of the delay which is randomized, 0 means fixed delay, 1 means full jitter
+	Jitter float64
+	// RetryOn, kinds of errors which are retried, matched by errors.Is
+	RetryOn []error
+}
+
+// DefaultRetryPolicy, 5 attempts of throttled requests, with full jitter between 50ms and 5s
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   50 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Jitter:      1,
+		RetryOn:     []error{ErrThrottled},
+	}
+}
+
+// WithRetryPolicy, requests are retried by given policy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(a *DynamoAccess) {
+		a.retryPolicy = policy
+	}
+}
+
+// waits between retries
+var retrySleep = time.Sleep
+
+func (p RetryPolicy) retryable(err error) bool {
+	for _, kind := range p.RetryOn {
+		if errors.Is(err, kind) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// delay, delay before retry of given number, counted from 1
+func (p RetryPolicy) delay(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		// doubling would overflow
+		if delay > math.MaxInt64/2 {
+			delay = math.MaxInt64
+			break
+		}
+		delay *= 2
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		delay -= time.Duration(rand.Float64() * p.Jitter * float64(delay))
+	}
+
+	return delay
+}
+
+// send, sends request until it succeeds, fails by error which is not retryable or attempts run out,
+// retries are counted in operation
+func (a *DynamoAccess) send(op *Operation, request func() error) error {
+	for attempt := 1; ; attempt++ {
+		a.rateLimiter.wait(op)
+
+		err := request()
+		if err == nil || attempt >= a.retryPolicy.MaxAttempts || !a.retryPolicy.retryable(translateError(op, err)) {
+			return err
+		}
+
+		op.Retries++
+		retrySleep(a.retryPolicy.delay(attempt))
+	}
+}
+
+// sendBatch, sends batch request until no items are left unprocessed, or attempts run out,
+// unprocessed items are retried by DefaultRetryPolicy when no policy is configured
+func (a *DynamoAccess) sendBatch(op *Operation, request func() (int, error)) error {
+	policy := a.retryPolicy
+	if !a.hasRetryPolicy() {
+		policy = DefaultRetryPolicy()
+	}
+
+	for attempt := 1; ; attempt++ {
+		unprocessed, err := request()
+		if err != nil {
+			return err
+		}
+
+		if unprocessed == 0 {
+			return nil
+		}
+
+		if attempt >= policy.MaxAttempts {
+			return ErrUnprocessedItems
+		}
+
+		op.Retries++
+		retrySleep(policy.delay(attempt))
+	}
+}
+
+// hasRetryPolicy, whether retry policy was configured, zero policy is not
+func (a *DynamoAccess) hasRetryPolicy() bool {
+	return a.retryPolicy.MaxAttempts != 0
+}
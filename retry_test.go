@@ -0,0 +1,150 @@
+package godynamo
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
+	"github.com/aws/aws-sdk-go-v2/aws/defaults"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/suite"
+	"math"
+	"testing"
+	"time"
+)
+
+type RetrySuite struct {
+	suite.Suite
+
+	delays []time.Duration
+}
+
+func (t *RetrySuite) SetupTest() {
+	t.delays = nil
+	retrySleep = func(d time.Duration) {
+		t.delays = append(t.delays, d)
+	}
+}
+
+func (t *RetrySuite) TearDownTest() {
+	retrySleep = time.Sleep
+}
+
+func (t *RetrySuite) TestDelay() {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	t.Equal(100*time.Millisecond, policy.delay(1))
+	t.Equal(200*time.Millisecond, policy.delay(2))
+	t.Equal(800*time.Millisecond, policy.delay(4))
+	t.Equal(time.Second, policy.delay(5))
+	t.Equal(time.Second, policy.delay(100))
+
+	// doubling is clamped, so large delays do not overflow
+	t.Equal(time.Duration(1<<62), RetryPolicy{BaseDelay: 1 << 61}.delay(2))
+	t.Equal(time.Duration(math.MaxInt64), RetryPolicy{BaseDelay: 1 << 61}.delay(3))
+	t.Equal(time.Hour, RetryPolicy{BaseDelay: time.Hour, MaxDelay: time.Hour}.delay(1000))
+	t.Equal(time.Duration(math.MaxInt64), RetryPolicy{BaseDelay: time.Hour}.delay(1000))
+
+	policy.Jitter = 0.5
+	for retry := 1; retry < 10; retry++ {
+		delay := policy.delay(retry)
+		t.True(delay <= RetryPolicy{BaseDelay: policy.BaseDelay, MaxDelay: policy.MaxDelay}.delay(retry))
+		t.True(delay >= RetryPolicy{BaseDelay: policy.BaseDelay, MaxDelay: policy.MaxDelay}.delay(retry)/2)
+	}
+}
+
+func (t *RetrySuite) TestSend() {
+	access := NewDynamoAccess(defaults.Config(), "x_", WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   10 * time.Millisecond,
+		MaxDelay:    time.Second,
+		RetryOn:     []error{ErrThrottled},
+	}))
+
+	throttled := awserr.New(dynamodb.ErrCodeProvisionedThroughputExceededException, "slow down", nil)
+
+	op := &Operation{Name: OperationCreate}
+	attempts := 0
+	t.Nil(access.send(op, func() error {
+		attempts++
+		if attempts < 3 {
+			return throttled
+		}
+		return nil
+	}))
+	t.Equal(3, attempts)
+	t.Equal(2, op.Retries)
+	t.Equal([]time.Duration{10 * time.Millisecond, 20 * time.Millisecond}, t.delays)
+
+	op = &Operation{Name: OperationCreate}
+	attempts = 0
+	t.Equal(throttled, access.send(op, func() error {
+		attempts++
+		return throttled
+	}))
+	t.Equal(3, attempts)
+	t.Equal(2, op.Retries)
+
+	op = &Operation{Name: OperationCreate}
+	failed := awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "failed", nil)
+	attempts = 0
+	t.Equal(failed, access.send(op, func() error {
+		attempts++
+		return failed
+	}))
+	t.Equal(1, attempts)
+	t.Equal(0, op.Retries)
+}
+
+func (t *RetrySuite) TestSendWithoutPolicy() {
+	access := NewDynamoAccess(defaults.Config(), "x_")
+	throttled := awserr.New(dynamodb.ErrCodeProvisionedThroughputExceededException, "slow down", nil)
+
+	attempts := 0
+	t.Equal(throttled, access.send(&Operation{}, func() error {
+		attempts++
+		return throttled
+	}))
+	t.Equal(1, attempts)
+	t.Empty(t.delays)
+}
+
+func (t *RetrySuite) TestSendBatch() {
+	access := NewDynamoAccess(defaults.Config(), "x_", WithRetryPolicy(DefaultRetryPolicy()))
+
+	op := &Operation{Name: OperationGetItems}
+	unprocessed := []int{5, 2, 0}
+	t.Nil(access.sendBatch(op, func() (int, error) {
+		left := unprocessed[0]
+		unprocessed = unprocessed[1:]
+		return left, nil
+	}))
+	t.Equal(2, op.Retries)
+	t.Len(t.delays, 2)
+
+	op = &Operation{Name: OperationGetItems}
+	t.Equal(ErrUnprocessedItems, access.sendBatch(op, func() (int, error) {
+		return 1, nil
+	}))
+	t.Equal(4, op.Retries)
+}
+
+func (t *RetrySuite) TestSendBatchWithoutPolicy() {
+	access := NewDynamoAccess(defaults.Config(), "x_")
+
+	op := &Operation{Name: OperationGetItems}
+	unprocessed := []int{5, 0}
+	t.Nil(access.sendBatch(op, func() (int, error) {
+		left := unprocessed[0]
+		unprocessed = unprocessed[1:]
+		return left, nil
+	}))
+	t.Equal(1, op.Retries)
+
+	op = &Operation{Name: OperationGetItems}
+	t.Equal(ErrUnprocessedItems, access.sendBatch(op, func() (int, error) {
+		return 1, nil
+	}))
+	t.Equal(DefaultRetryPolicy().MaxAttempts-1, op.Retries)
+}
+
+func TestRetrySuite(t *testing.T) {
+	suite.Run(t, new(RetrySuite))
+}
@@ -0,0 +1,201 @@
+package godynamo
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// ScanMode, whether scans are allowed without explicit opt in by AllowScan
+type ScanMode int
+
+const (
+	// ScanAllow, scans are allowed
+	ScanAllow ScanMode = iota
+	// ScanWarn, scans are allowed, but those without opt in are reported as warning, nothing is done without Report
+	ScanWarn
+	// ScanForbid, scans without opt in fail by ErrScanForbidden
+	ScanForbid
+)
+
+// ScanPolicy, guards Scan, ScanByAttribute, ScanByFilter and DumpTable
+type ScanPolicy struct {
+	Mode ScanMode
+	// MaxItems, maximal number of items read by one scan, or by all scans of access returned by AllowScan,
+	// limit of scan is cut down to the rest of budget, zero is unlimited
+	MaxItems int64
+	// MaxPages, maximal number of requests of all scans of access returned by AllowScan, zero is unlimited
+	MaxPages int
+	// Report, called before every scan, e.g. to find call sites of scans
+	Report func(report ScanReport)
+}
+
+// ScanReport, describes scan and where it was called from
+type ScanReport struct {
+	Table string
+	Index string
+	// Caller, file and line of the call of DynamoAccess
+	Caller string
+	// Function, name of calling function
+	Function string
+	// OptedIn, scan is called by access returned by AllowScan
+	OptedIn bool
+	// Warning, scan without opt in under ScanWarn, or scan which limit is cut down by MaxItems
+	Warning bool
+	// Forbidden, scan without opt in under ScanForbid, it is not sent
+	Forbidden bool
+	// Truncated, limit of scan is cut down to the rest of MaxItems, it may return less items than requested
+	Truncated bool
+	// Exceeded, budget of MaxItems or MaxPages is spent, scan is not sent
+	Exceeded bool
+}
+
+// scanBudget, items and pages read or reserved by scans sharing the budget
+type scanBudget struct {
+	mutex sync.Mutex
+	items int64
+	pages int
+}
+
+// scanReservation, page and items reserved by scan before it is sent
+type scanReservation struct {
+	budget *scanBudget
+	items  int64
+}
+
+// WithScanPolicy, scans are guarded by given policy, every scan has its own budget, unless it is called
+// by access returned by AllowScan
+func WithScanPolicy(policy ScanPolicy) Option {
+	return func(a *DynamoAccess) {
+		a.scanPolicy = policy
+	}
+}
+
+// AllowScan, copy of access, which scans are explicitly allowed, MaxItems and MaxPages of the policy
+// are shared by all its scans, apart from other scans of the access, e.g. paging through the table in a job
+func (a *DynamoAccess) AllowScan() *DynamoAccess {
+	allowed := *a
+	allowed.scanAllowed = true
+	allowed.scanBudget = &scanBudget{}
+	return &allowed
+}
+
+// guardScan, reports scan and checks it against the policy, page and items of the scan are reserved from budget,
+// limit of the scan is cut down to the rest of budget, reservation must be settled by settleScan
+func (a *DynamoAccess) guardScan(op *Operation) (*scanReservation, error) {
+	policy := a.scanPolicy
+
+	report := ScanReport{
+		Table:     op.Table,
+		Index:     op.Input.IndexName,
+		OptedIn:   a.scanAllowed,
+		Warning:   !a.scanAllowed && policy.Mode == ScanWarn,
+		Forbidden: !a.scanAllowed && policy.Mode == ScanForbid,
+	}
+	report.Caller, report.Function = callSite()
+
+	var reservation *scanReservation
+	var exceeded error
+	if !report.Forbidden {
+		reservation, exceeded = a.reserveScan(op)
+		report.Exceeded = exceeded != nil
+		report.Truncated = reservation != nil && reservation.truncated(op.Input.Limit)
+		report.Warning = report.Warning || report.Truncated
+	}
+
+	if policy.Report != nil {
+		policy.Report(report)
+	}
+
+	if report.Forbidden {
+		return nil, fmt.Errorf("%w: table %s at %s", ErrScanForbidden, report.Table, report.Caller)
+	}
+	if exceeded != nil {
+		return nil, exceeded
+	}
+
+	if reservation != nil && report.Truncated {
+		op.Input.Limit = reservation.items
+	}
+
+	return reservation, nil
+}
+
+// reserveScan, reserves page and items of limit of the scan, or the rest of MaxItems when it is lower,
+// under lock of the budget, so concurrent scans can not overspend it
+func (a *DynamoAccess) reserveScan(op *Operation) (*scanReservation, error) {
+	budget, policy := a.scanBudget, a.scanPolicy
+	if policy.MaxItems == 0 && policy.MaxPages == 0 {
+		return nil, nil
+	}
+	// scan without opt in is budgeted alone
+	if budget == nil {
+		budget = &scanBudget{}
+	}
+
+	budget.mutex.Lock()
+	defer budget.mutex.Unlock()
+
+	if policy.MaxPages > 0 && budget.pages >= policy.MaxPages {
+		return nil, fmt.Errorf("%w: %d pages of table %s read", ErrScanBudgetExceeded, budget.pages, op.Table)
+	}
+
+	reservation := &scanReservation{budget: budget, items: op.Input.Limit}
+	if policy.MaxItems > 0 {
+		remaining := policy.MaxItems - budget.items
+		if remaining <= 0 {
+			return nil, fmt.Errorf("%w: %d items of table %s read", ErrScanBudgetExceeded, budget.items, op.Table)
+		}
+		if reservation.items == 0 || reservation.items > remaining {
+			reservation.items = remaining
+		}
+	}
+
+	budget.pages++
+	budget.items += reservation.items
+	return reservation, nil
+}
+
+// truncated, whether reserved items are less than limit of the scan
+func (r *scanReservation) truncated(limit int64) bool {
+	return r.items > 0 && (limit == 0 || limit > r.items)
+}
+
+// settleScan, replaces reserved items by items read by scan, page and items are released when scan failed
+func (a *DynamoAccess) settleScan(reservation *scanReservation, result *dynamodb.ScanOutput) {
+	if reservation == nil {
+		return
+	}
+
+	budget := reservation.budget
+	budget.mutex.Lock()
+	defer budget.mutex.Unlock()
+
+	budget.items -= reservation.items
+	if result == nil {
+		budget.pages--
+		return
+	}
+	budget.items += aws.Int64Value(result.ScannedCount)
+}
+
+// callSite, file, line and function of the first caller outside of DynamoAccess
+func callSite() (string, string) {
+	prefix := reflect.TypeOf(DynamoAccess{}).PkgPath() + ".(*DynamoAccess)."
+
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, prefix) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line), frame.Function
+		}
+		if !more {
+			return "", ""
+		}
+	}
+}
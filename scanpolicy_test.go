@@ -0,0 +1,171 @@
+package godynamo
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/defaults"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type ScanPolicySuite struct {
+	suite.Suite
+
+	reports []ScanReport
+}
+
+func (t *ScanPolicySuite) SetupTest() {
+	t.reports = nil
+}
+
+func (t *ScanPolicySuite) access(policy ScanPolicy) *DynamoAccess {
+	policy.Report = func(report ScanReport) {
+		t.reports = append(t.reports, report)
+	}
+	return NewDynamoAccess(defaults.Config(), "x_", WithScanPolicy(policy))
+}
+
+func (t *ScanPolicySuite) TestForbid() {
+	access := t.access(ScanPolicy{Mode: ScanForbid})
+
+	_, err := access.guardScan(&Operation{Name: OperationScan, Table: "x_aaa"})
+	t.True(errors.Is(err, ErrScanForbidden))
+	t.Len(t.reports, 1)
+	t.True(t.reports[0].Forbidden)
+	t.False(t.reports[0].OptedIn)
+	t.Equal("x_aaa", t.reports[0].Table)
+	t.True(strings.Contains(t.reports[0].Caller, "scanpolicy_test.go:"), t.reports[0].Caller)
+	t.True(strings.HasSuffix(t.reports[0].Function, ".(*ScanPolicySuite).TestForbid"), t.reports[0].Function)
+
+	_, err = access.AllowScan().guardScan(&Operation{Name: OperationScan, Table: "x_aaa"})
+	t.Nil(err)
+	t.Len(t.reports, 2)
+	t.True(t.reports[1].OptedIn)
+	t.False(t.reports[1].Forbidden)
+}
+
+func (t *ScanPolicySuite) TestDumpTable() {
+	access := t.access(ScanPolicy{Mode: ScanForbid})
+
+	_, err := access.DumpTable(&aaa{})
+	t.True(errors.Is(err, ErrScanForbidden))
+	t.Len(t.reports, 1)
+	t.Equal("x_aaa", t.reports[0].Table)
+	t.True(strings.HasSuffix(t.reports[0].Function, ".(*ScanPolicySuite).TestDumpTable"), t.reports[0].Function)
+}
+
+func (t *ScanPolicySuite) TestWarn() {
+	access := t.access(ScanPolicy{Mode: ScanWarn})
+
+	_, err := access.guardScan(&Operation{Name: OperationScan, Table: "x_aaa", Input: RequestInput{IndexName: "index"}})
+	t.Nil(err)
+	t.Len(t.reports, 1)
+	t.True(t.reports[0].Warning)
+	t.Equal("index", t.reports[0].Index)
+
+	_, err = access.AllowScan().guardScan(&Operation{Name: OperationScan, Table: "x_aaa"})
+	t.Nil(err)
+	t.False(t.reports[1].Warning)
+}
+
+func (t *ScanPolicySuite) TestMaxItems() {
+	access := t.access(ScanPolicy{MaxItems: 100})
+
+	op := &Operation{Name: OperationScan, Table: "x_aaa", Input: RequestInput{Limit: 10}}
+	reservation, err := access.guardScan(op)
+	t.Nil(err)
+	t.Equal(int64(10), op.Input.Limit)
+	t.False(t.reports[0].Truncated)
+	access.settleScan(reservation, &dynamodb.ScanOutput{ScannedCount: aws.Int64(10)})
+
+	// every scan without opt in has its own budget, truncated scan is reported
+	for i := 0; i < 2; i++ {
+		op = &Operation{Name: OperationScan, Table: "x_aaa"}
+		reservation, err = access.guardScan(op)
+		t.Nil(err)
+		t.Equal(int64(100), op.Input.Limit)
+		t.True(t.reports[len(t.reports)-1].Truncated)
+		t.True(t.reports[len(t.reports)-1].Warning)
+		access.settleScan(reservation, &dynamodb.ScanOutput{ScannedCount: aws.Int64(100)})
+	}
+
+	allowed := access.AllowScan()
+	op = &Operation{Name: OperationScan, Table: "x_aaa", Input: RequestInput{Limit: 70}}
+	reservation, err = allowed.guardScan(op)
+	t.Nil(err)
+	allowed.settleScan(reservation, &dynamodb.ScanOutput{ScannedCount: aws.Int64(70)})
+
+	op = &Operation{Name: OperationScan, Table: "x_aaa", Input: RequestInput{Limit: 50}}
+	reservation, err = allowed.guardScan(op)
+	t.Nil(err)
+	t.Equal(int64(30), op.Input.Limit)
+	allowed.settleScan(reservation, &dynamodb.ScanOutput{ScannedCount: aws.Int64(30)})
+
+	_, err = allowed.guardScan(&Operation{Name: OperationScan, Table: "x_aaa"})
+	t.True(errors.Is(err, ErrScanBudgetExceeded))
+
+	// budget is not shared by other copies
+	_, err = access.AllowScan().guardScan(&Operation{Name: OperationScan, Table: "x_aaa"})
+	t.Nil(err)
+}
+
+func (t *ScanPolicySuite) TestMaxPages() {
+	access := t.access(ScanPolicy{MaxPages: 1})
+
+	// pages of scans without opt in are not summed up
+	for i := 0; i < 2; i++ {
+		reservation, err := access.guardScan(&Operation{Name: OperationScan, Table: "x_aaa"})
+		t.Nil(err)
+		access.settleScan(reservation, &dynamodb.ScanOutput{ScannedCount: aws.Int64(10)})
+	}
+
+	allowed := t.access(ScanPolicy{Mode: ScanForbid, MaxPages: 2}).AllowScan()
+	for i := 0; i < 2; i++ {
+		reservation, err := allowed.guardScan(&Operation{Name: OperationScan, Table: "x_aaa"})
+		t.Nil(err)
+		allowed.settleScan(reservation, &dynamodb.ScanOutput{ScannedCount: aws.Int64(10)})
+	}
+
+	_, err := allowed.guardScan(&Operation{Name: OperationScan, Table: "x_aaa"})
+	t.True(errors.Is(err, ErrScanBudgetExceeded))
+	t.EqualError(err, "scan budget exceeded: 2 pages of table x_aaa read")
+}
+
+func (t *ScanPolicySuite) TestReservation() {
+	access := t.access(ScanPolicy{MaxItems: 100, MaxPages: 3}).AllowScan()
+
+	// concurrent scans can not reserve more than the budget
+	var group sync.WaitGroup
+	var mutex sync.Mutex
+	var pages int
+	for i := 0; i < 10; i++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			op := &Operation{Name: OperationScan, Table: "x_aaa", Input: RequestInput{Limit: 40}}
+			if _, err := access.reserveScan(op); err == nil {
+				mutex.Lock()
+				pages++
+				mutex.Unlock()
+			}
+		}()
+	}
+	group.Wait()
+	t.Equal(3, pages)
+	t.Equal(int64(100), access.scanBudget.items)
+
+	// failed scan releases its reservation
+	access = t.access(ScanPolicy{MaxPages: 1}).AllowScan()
+	reservation, err := access.guardScan(&Operation{Name: OperationScan, Table: "x_aaa"})
+	t.Nil(err)
+	access.settleScan(reservation, nil)
+	_, err = access.guardScan(&Operation{Name: OperationScan, Table: "x_aaa"})
+	t.Nil(err)
+}
+
+func TestScanPolicySuite(t *testing.T) {
+	suite.Run(t, new(ScanPolicySuite))
+}
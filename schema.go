@@ -0,0 +1,250 @@
+package godynamo
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"strings"
+)
+
+// KeySchema, names of hash and range attribute of table or index,
+// Range is empty when there is no range key
+type KeySchema struct {
+	Hash  string
+	Range string
+}
+
+// AttributeSchema, definition of attribute used in any key schema
+type AttributeSchema struct {
+	Name string
+	Type dynamodb.ScalarAttributeType
+}
+
+// Throughput, provisioned read and write capacity units
+type Throughput struct {
+	Read  int64
+	Write int64
+}
+
+// IndexSchema, description of secondary index,
+// Throughput is nil for local secondary indexes
+type IndexSchema struct {
+	Name       string
+	Key        KeySchema
+	Projection dynamodb.ProjectionType
+	Throughput *Throughput
+}
+
+// TableSchema, description of the table, which is created for model
+type TableSchema struct {
+	Table         string
+	Key           KeySchema
+	Attributes    []AttributeSchema
+	GlobalIndexes []IndexSchema
+	LocalIndexes  []IndexSchema
+	Throughput    Throughput
+
+	// TTL, name of attribute tagged as ttl, empty when items do not expire
+	TTL string
+
+	Settings TableSettings
+}
+
+// Schema, returns description of table which CreateTables creates for given model, nothing is sent to db
+func (a *DynamoAccess) Schema(item interface{}) (*TableSchema, error) {
+	input, err := a.tableInput(item)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &TableSchema{
+		Table:      *input.TableName,
+		Key:        keySchemaOf(input.KeySchema),
+		Throughput: throughputOf(input.ProvisionedThroughput),
+		Settings:   a.settings(item),
+	}
+
+	schema.TTL, err = a.ttlAttribute(item)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, attribute := range input.AttributeDefinitions {
+		schema.Attributes = append(schema.Attributes, AttributeSchema{
+			Name: *attribute.AttributeName,
+			Type: attribute.AttributeType,
+		})
+	}
+
+	for _, index := range input.GlobalSecondaryIndexes {
+		throughput := throughputOf(index.ProvisionedThroughput)
+		schema.GlobalIndexes = append(schema.GlobalIndexes, IndexSchema{
+			Name:       *index.IndexName,
+			Key:        keySchemaOf(index.KeySchema),
+			Projection: index.Projection.ProjectionType,
+			Throughput: &throughput,
+		})
+	}
+
+	for _, index := range input.LocalSecondaryIndexes {
+		schema.LocalIndexes = append(schema.LocalIndexes, IndexSchema{
+			Name:       *index.IndexName,
+			Key:        keySchemaOf(index.KeySchema),
+			Projection: index.Projection.ProjectionType,
+		})
+	}
+
+	return schema, nil
+}
+
+// CreateTableInput, request which creates described table
+func (s *TableSchema) CreateTableInput() *dynamodb.CreateTableInput {
+	input := &dynamodb.CreateTableInput{
+		TableName:             aws.String(s.Table),
+		KeySchema:             s.Key.elements(),
+		ProvisionedThroughput: s.Throughput.provisioned(),
+		StreamSpecification:   s.Settings.streamSpecification(),
+		SSESpecification:      s.Settings.sseSpecification(),
+	}
+
+	for _, attribute := range s.Attributes {
+		input.AttributeDefinitions = append(input.AttributeDefinitions, dynamodb.AttributeDefinition{
+			AttributeName: aws.String(attribute.Name),
+			AttributeType: attribute.Type,
+		})
+	}
+
+	for _, index := range s.GlobalIndexes {
+		globalIndex := dynamodb.GlobalSecondaryIndex{
+			IndexName:  aws.String(index.Name),
+			KeySchema:  index.Key.elements(),
+			Projection: &dynamodb.Projection{ProjectionType: index.Projection},
+		}
+		if index.Throughput != nil {
+			globalIndex.ProvisionedThroughput = index.Throughput.provisioned()
+		}
+
+		input.GlobalSecondaryIndexes = append(input.GlobalSecondaryIndexes, globalIndex)
+	}
+
+	for _, index := range s.LocalIndexes {
+		input.LocalSecondaryIndexes = append(input.LocalSecondaryIndexes, dynamodb.LocalSecondaryIndex{
+			IndexName:  aws.String(index.Name),
+			KeySchema:  index.Key.elements(),
+			Projection: &dynamodb.Projection{ProjectionType: index.Projection},
+		})
+	}
+
+	return input
+}
+
+// AttributeType, type of attribute used in any key schema
+func (s *TableSchema) AttributeType(name string) (dynamodb.ScalarAttributeType, bool) {
+	for _, attribute := range s.Attributes {
+		if attribute.Name == name {
+			return attribute.Type, true
+		}
+	}
+
+	return "", false
+}
+
+func (s *TableSchema) String() string {
+	lines := []string{
+		fmt.Sprintf("table %s (%s) throughput %s", s.Table, s.Key, s.Throughput),
+	}
+
+	for _, attribute := range s.Attributes {
+		lines = append(lines, fmt.Sprintf("  attribute %s %s", attribute.Name, attribute.Type))
+	}
+
+	for _, index := range s.GlobalIndexes {
+		lines = append(lines, fmt.Sprintf("  global secondary index %s (%s) projection %s throughput %s",
+			index.Name, index.Key, index.Projection, index.Throughput))
+	}
+
+	for _, index := range s.LocalIndexes {
+		lines = append(lines, fmt.Sprintf("  local secondary index %s (%s) projection %s",
+			index.Name, index.Key, index.Projection))
+	}
+
+	if s.TTL != "" {
+		lines = append(lines, fmt.Sprintf("  ttl %s", s.TTL))
+	}
+
+	if s.Settings.Stream != "" {
+		lines = append(lines, fmt.Sprintf("  stream %s", s.Settings.Stream))
+	}
+
+	if s.Settings.Encryption {
+		lines = append(lines, "  encryption enabled")
+	}
+
+	if s.Settings.PointInTimeRecovery {
+		lines = append(lines, "  point in time recovery enabled")
+	}
+
+	for _, tag := range s.Settings.tags() {
+		lines = append(lines, fmt.Sprintf("  tag %s = %s", *tag.Key, *tag.Value))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (k KeySchema) String() string {
+	if k.Range == "" {
+		return fmt.Sprintf("%s HASH", k.Hash)
+	}
+
+	return fmt.Sprintf("%s HASH, %s RANGE", k.Hash, k.Range)
+}
+
+func (t Throughput) String() string {
+	return fmt.Sprintf("read %d, write %d", t.Read, t.Write)
+}
+
+func (k KeySchema) elements() []dynamodb.KeySchemaElement {
+	elements := []dynamodb.KeySchemaElement{
+		{AttributeName: aws.String(k.Hash), KeyType: dynamodb.KeyTypeHash},
+	}
+
+	if k.Range != "" {
+		elements = append(elements, dynamodb.KeySchemaElement{
+			AttributeName: aws.String(k.Range), KeyType: dynamodb.KeyTypeRange,
+		})
+	}
+
+	return elements
+}
+
+func (t Throughput) provisioned() *dynamodb.ProvisionedThroughput {
+	return &dynamodb.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(t.Read),
+		WriteCapacityUnits: aws.Int64(t.Write),
+	}
+}
+
+func keySchemaOf(elements []dynamodb.KeySchemaElement) KeySchema {
+	key := KeySchema{}
+	for _, element := range elements {
+		switch element.KeyType {
+		case dynamodb.KeyTypeHash:
+			key.Hash = *element.AttributeName
+		case dynamodb.KeyTypeRange:
+			key.Range = *element.AttributeName
+		}
+	}
+
+	return key
+}
+
+func throughputOf(throughput *dynamodb.ProvisionedThroughput) Throughput {
+	if throughput == nil {
+		return Throughput{}
+	}
+
+	return Throughput{
+		Read:  aws.Int64Value(throughput.ReadCapacityUnits),
+		Write: aws.Int64Value(throughput.WriteCapacityUnits),
+	}
+}
@@ -0,0 +1,210 @@
+package godynamo
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/defaults"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/flowup-labs/godynamo/godynamotest"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type SchemaSuite struct {
+	suite.Suite
+
+	access *DynamoAccess
+}
+
+func (t *SchemaSuite) SetupSuite() {
+	t.access = NewDynamoAccess(defaults.Config(), "schema_")
+}
+
+func (t *SchemaSuite) TestSchema() {
+	schema, err := t.access.Schema(&user{})
+	t.Nil(err)
+
+	expected := &TableSchema{
+		Table: "schema_user",
+		Key:   KeySchema{Hash: "email"},
+		Attributes: []AttributeSchema{
+			{Name: "first_name", Type: dynamodb.ScalarAttributeTypeS},
+			{Name: "email", Type: dynamodb.ScalarAttributeTypeS},
+			{Name: "created_at", Type: dynamodb.ScalarAttributeTypeN},
+		},
+		GlobalIndexes: []IndexSchema{
+			{
+				Name:       "created_at_first_name_index",
+				Key:        KeySchema{Hash: "created_at", Range: "first_name"},
+				Projection: dynamodb.ProjectionTypeAll,
+				Throughput: &Throughput{Read: 10, Write: 10},
+			},
+		},
+		Throughput: Throughput{Read: 10, Write: 10},
+		Settings:   TableSettings{Tags: map[string]string{}},
+	}
+
+	t.Equal(expected, schema)
+}
+
+func (t *SchemaSuite) TestSchemaLocalIndex() {
+	schema, err := t.access.Schema(&fff{})
+	t.Nil(err)
+
+	t.Equal(KeySchema{Hash: "id", Range: "ffb"}, schema.Key)
+	t.Len(schema.LocalIndexes, 1)
+	t.Equal(KeySchema{Hash: "id", Range: "ffc"}, schema.LocalIndexes[0].Key)
+	t.Nil(schema.LocalIndexes[0].Throughput)
+}
+
+func (t *SchemaSuite) TestCreateTableInput() {
+	for _, item := range []interface{}{&user{}, &fff{}, &eee{}} {
+		expected, err := t.access.tableInput(item)
+		t.Nil(err)
+
+		schema, err := t.access.Schema(item)
+		t.Nil(err)
+
+		t.Equal(expected, schema.CreateTableInput())
+	}
+}
+
+func (t *SchemaSuite) TestSchemaAttributeNames() {
+	schema, err := t.access.Schema(&iii{})
+	t.Nil(err)
+
+	t.Equal([]AttributeSchema{
+		{Name: "id", Type: dynamodb.ScalarAttributeTypeS},
+		{Name: "iia", Type: dynamodb.ScalarAttributeTypeS},
+		{Name: "Ib", Type: dynamodb.ScalarAttributeTypeN},
+	}, schema.Attributes)
+	t.Equal(KeySchema{Hash: "iia", Range: "Ib"}, schema.GlobalIndexes[0].Key)
+}
+
+func (t *SchemaSuite) TestSchemaTTL() {
+	schema, err := t.access.Schema(&ggg{})
+	t.Nil(err)
+
+	t.Equal("expires", schema.TTL)
+	t.Equal([]AttributeSchema{{Name: "id", Type: dynamodb.ScalarAttributeTypeS}}, schema.Attributes)
+
+	schema, err = t.access.Schema(&user{})
+	t.Nil(err)
+	t.Equal("", schema.TTL)
+}
+
+func (t *SchemaSuite) TestTimeToLiveChanges() {
+	description := func(status dynamodb.TimeToLiveStatus, attribute string) *dynamodb.TimeToLiveDescription {
+		return &dynamodb.TimeToLiveDescription{TimeToLiveStatus: status, AttributeName: aws.String(attribute)}
+	}
+	types := func(changes []SchemaChange) []SchemaChangeType {
+		var changeTypes []SchemaChangeType
+		for _, change := range changes {
+			changeTypes = append(changeTypes, change.Type)
+		}
+		return changeTypes
+	}
+
+	t.Nil(timeToLiveChanges(nil, ""))
+	t.Equal([]SchemaChangeType{SchemaChangeTimeToLive}, types(timeToLiveChanges(nil, "expires")))
+	t.Nil(timeToLiveChanges(description(dynamodb.TimeToLiveStatusEnabling, "expires"), "expires"))
+	t.Equal([]SchemaChangeType{SchemaChangeTimeToLive}, types(timeToLiveChanges(description(dynamodb.TimeToLiveStatusEnabled, "expires"), "")))
+	t.Nil(timeToLiveChanges(description(dynamodb.TimeToLiveStatusDisabling, "expires"), ""))
+
+	// db rejects another update of ttl until the previous one is done
+	changes := timeToLiveChanges(description(dynamodb.TimeToLiveStatusEnabled, "expires"), "expires_at")
+	t.Equal([]SchemaChangeType{SchemaChangeUnsupported}, types(changes))
+	t.Equal("change ttl attribute expires -> expires_at, ttl has to be disabled first", changes[0].Description)
+
+	changes = timeToLiveChanges(description(dynamodb.TimeToLiveStatusDisabling, "expires"), "expires")
+	t.Equal([]SchemaChangeType{SchemaChangeUnsupported}, types(changes))
+	t.Equal("enable ttl on attribute expires, ttl on attribute expires is still disabling", changes[0].Description)
+
+	changes = timeToLiveChanges(description(dynamodb.TimeToLiveStatusEnabling, "expires"), "")
+	t.Equal([]SchemaChangeType{SchemaChangeUnsupported}, types(changes))
+}
+
+func (t *SchemaSuite) TestExpiresIn() {
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	access := NewDynamoAccess(defaults.Config(), "schema_", WithClock(godynamotest.NewClock(start)))
+
+	item := &ggg{}
+	t.Nil(access.ExpiresIn(item, time.Hour))
+	t.Equal(start.Add(time.Hour).Unix(), item.Expires)
+}
+
+func (t *SchemaSuite) TestExpiresAt() {
+	at := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	item := &ggg{}
+	t.Nil(t.access.ExpiresAt(item, at))
+	t.Equal(at.Unix(), item.Expires)
+
+	t.Equal(ErrNoTTL, t.access.ExpiresAt(&user{}, at))
+	t.Equal(ErrNotPointer, t.access.ExpiresAt(ggg{}, at))
+}
+
+func (t *SchemaSuite) TestSchemaSettings() {
+	access := NewDynamoAccess(defaults.Config(), "schema_", WithTableSettings(TableSettings{
+		Encryption: true,
+		Tags:       map[string]string{"env": "test"},
+	}))
+
+	schema, err := access.Schema(&hhh{})
+	t.Nil(err)
+	t.Equal(TableSettings{
+		Stream:     dynamodb.StreamViewTypeNewAndOldImages,
+		Encryption: true,
+		Tags:       map[string]string{"env": "test", "team": "core"},
+	}, schema.Settings)
+
+	input := schema.CreateTableInput()
+	t.True(*input.StreamSpecification.StreamEnabled)
+	t.True(*input.SSESpecification.Enabled)
+
+	// provider is resolved on pointer of model of slices and values
+	for _, item := range []interface{}{&[]hhh{}, hhh{}} {
+		t.Equal(dynamodb.StreamViewTypeNewAndOldImages, access.settings(item).Stream)
+	}
+
+	schema, err = access.Schema(&user{})
+	t.Nil(err)
+	t.Equal(TableSettings{
+		Encryption: true,
+		Tags:       map[string]string{"env": "test"},
+	}, schema.Settings)
+	t.Nil(schema.CreateTableInput().StreamSpecification)
+}
+
+func (t *SchemaSuite) TestWaitForTableTimeout() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.Write([]byte(`{"Table": {"TableName": "schema_user", "TableStatus": "UPDATING"}}`))
+	}))
+	defer server.Close()
+
+	interval := schemaPollInterval
+	schemaPollInterval = time.Millisecond
+	defer func() {
+		schemaPollInterval = interval
+	}()
+
+	config := defaults.Config()
+	config.Region = "mock-region"
+	config.EndpointResolver = aws.ResolveWithEndpointURL(server.URL)
+	config.Credentials = aws.StaticCredentialsProvider{Value: aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}}
+	// every poll moves clock by 10 minutes, deadline passes after few polls
+	clock := godynamotest.NewSteppingClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 10*time.Minute)
+	access := NewDynamoAccess(config, "schema_", WithSchemaTimeout(time.Hour), WithClock(clock))
+
+	err := access.waitForTable(aws.String("schema_user"))
+	t.True(errors.Is(err, ErrTableNotActive))
+	t.EqualError(err, "table is not active: table schema_user or its indexes after 1h0m0s")
+}
+
+func TestSchemaSuite(t *testing.T) {
+	suite.Run(t, &SchemaSuite{})
+}
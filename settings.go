@@ -0,0 +1,157 @@
+package godynamo
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"reflect"
+	"sort"
+)
+
+// TableSettings, settings of the table, which are not declared by tags of model
+type TableSettings struct {
+	// Stream, view type of DynamoDB Stream, stream is disabled when empty
+	Stream dynamodb.StreamViewType
+
+	// Encryption, server-side encryption at rest
+	Encryption bool
+
+	// PointInTimeRecovery, continuous backups of the table
+	PointInTimeRecovery bool
+
+	// Tags, resource tags of the table
+	Tags map[string]string
+}
+
+// TableSettingsProvider, model which declares its own settings of the table,
+// defaults are the settings of DynamoAccess
+type TableSettingsProvider interface {
+	TableSettings(defaults TableSettings) TableSettings
+}
+
+// WithTableSettings, default settings of all tables created or migrated by access
+func WithTableSettings(settings TableSettings) Option {
+	return func(a *DynamoAccess) {
+		a.tableSettings = settings
+	}
+}
+
+// settings, returns table settings of given model
+func (a *DynamoAccess) settings(item interface{}) TableSettings {
+	defaults := a.tableSettings
+
+	// model can not change tags of other tables
+	defaults.Tags = make(map[string]string, len(a.tableSettings.Tags))
+	for key, value := range a.tableSettings.Tags {
+		defaults.Tags[key] = value
+	}
+
+	if provider, ok := item.(TableSettingsProvider); ok {
+		return provider.TableSettings(defaults)
+	} else if t := modelType(item); t != nil {
+		if provider, ok := reflect.New(t).Interface().(TableSettingsProvider); ok {
+			return provider.TableSettings(defaults)
+		}
+	}
+
+	return defaults
+}
+
+// streamSpecification, nil when stream is disabled
+func (s TableSettings) streamSpecification() *dynamodb.StreamSpecification {
+	if s.Stream == "" {
+		return nil
+	}
+
+	return &dynamodb.StreamSpecification{
+		StreamEnabled:  aws.Bool(true),
+		StreamViewType: s.Stream,
+	}
+}
+
+// sseSpecification, nil when encryption is disabled
+func (s TableSettings) sseSpecification() *dynamodb.SSESpecification {
+	if !s.Encryption {
+		return nil
+	}
+
+	return &dynamodb.SSESpecification{
+		Enabled: aws.Bool(true),
+	}
+}
+
+// tags, resource tags sorted by key
+func (s TableSettings) tags() []dynamodb.Tag {
+	keys := make([]string, 0, len(s.Tags))
+	for key := range s.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tags := make([]dynamodb.Tag, 0, len(keys))
+	for _, key := range keys {
+		tags = append(tags, dynamodb.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(s.Tags[key]),
+		})
+	}
+
+	return tags
+}
+
+// applyPointInTimeRecovery, table has to be active
+func (a *DynamoAccess) applyPointInTimeRecovery(tableName *string, enabled bool) error {
+	_, err := a.svc.UpdateContinuousBackupsRequest(&dynamodb.UpdateContinuousBackupsInput{
+		TableName: tableName,
+		PointInTimeRecoverySpecification: &dynamodb.PointInTimeRecoverySpecification{
+			PointInTimeRecoveryEnabled: aws.Bool(enabled),
+		},
+	}).Send()
+
+	return err
+}
+
+// applyTags, adds or updates given tags of the table
+func (a *DynamoAccess) applyTags(tableArn *string, tags []dynamodb.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err := a.svc.TagResourceRequest(&dynamodb.TagResourceInput{
+		ResourceArn: tableArn,
+		Tags:        tags,
+	}).Send()
+
+	return err
+}
+
+// configureTable, applies settings which can not be part of create table request
+func (a *DynamoAccess) configureTable(item interface{}, table *dynamodb.TableDescription) error {
+	ttl, err := a.ttlAttribute(item)
+	if err != nil {
+		return err
+	}
+
+	settings := a.settings(item)
+
+	if ttl == "" && !settings.PointInTimeRecovery && len(settings.Tags) == 0 {
+		return nil
+	}
+
+	if err := a.waitForTable(table.TableName); err != nil {
+		return err
+	}
+
+	if ttl != "" {
+		if err := a.applyTimeToLive(table.TableName, ttl, true); err != nil {
+			return err
+		}
+	}
+
+	if settings.PointInTimeRecovery {
+		if err := a.applyPointInTimeRecovery(table.TableName, true); err != nil {
+			return err
+		}
+	}
+
+	return a.applyTags(table.TableArn, settings.tags())
+}
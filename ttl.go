@@ -0,0 +1,138 @@
+package godynamo
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExpiresIn, sets attribute tagged as ttl of given item, so item expires after given duration
+func (a *DynamoAccess) ExpiresIn(item interface{}, duration time.Duration) error {
+	return a.ExpiresAt(item, now(a.clock).Add(duration))
+}
+
+// ExpiresAt, sets attribute tagged as ttl of given item, so item expires at given time
+func (a *DynamoAccess) ExpiresAt(item interface{}, at time.Time) error {
+	v := reflect.ValueOf(item)
+	if v.Kind() != reflect.Ptr {
+		return ErrNotPointer
+	}
+
+	if v.IsNil() {
+		return ErrElemNil
+	}
+
+	_, index, err := ttlField(v.Type())
+	if err != nil {
+		return err
+	}
+
+	if index == nil {
+		return ErrNoTTL
+	}
+
+	field := fieldByIndex(v.Elem(), index)
+	switch field.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		field.SetUint(uint64(at.Unix()))
+	default:
+		field.SetInt(at.Unix())
+	}
+
+	return nil
+}
+
+// ttlAttribute, returns name of attribute tagged as ttl, empty when model has no ttl
+func (a *DynamoAccess) ttlAttribute(item interface{}) (string, error) {
+	t := reflect.TypeOf(item)
+	if t == nil {
+		return "", ErrElemNil
+	}
+
+	name, _, err := ttlField(t)
+	return name, err
+}
+
+// ttlField, finds field tagged as ttl, returns name of its attribute and index of the field
+func ttlField(t reflect.Type) (string, []int, error) {
+	fields, _ := modelFields(t)
+	for _, field := range fields {
+		dynamoTag, ok := field.Tag.Lookup("godynamo")
+		if !ok || !hasTagFunc(dynamoTag, "ttl") {
+			continue
+		}
+
+		switch field.Type.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			return "", nil, ErrNotSupportedType
+		}
+
+		return field.attribute, field.index, nil
+	}
+
+	return "", nil, nil
+}
+
+func hasTagFunc(dynamoTag, name string) bool {
+	for _, dynamoFunc := range strings.Split(dynamoTag, ",") {
+		if dynamoFunc == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyTimeToLive, enables ttl on the table, table has to be active
+func (a *DynamoAccess) applyTimeToLive(tableName *string, attribute string, enabled bool) error {
+	_, err := a.svc.UpdateTimeToLiveRequest(&dynamodb.UpdateTimeToLiveInput{
+		TableName: tableName,
+		TimeToLiveSpecification: &dynamodb.TimeToLiveSpecification{
+			AttributeName: aws.String(attribute),
+			Enabled:       aws.Bool(enabled),
+		},
+	}).Send()
+
+	return err
+}
+
+// isExpired, item has ttl attribute set and the time already passed
+func isExpired(av map[string]dynamodb.AttributeValue, attribute string, now time.Time) bool {
+	if attribute == "" || av[attribute].N == nil {
+		return false
+	}
+
+	expires, err := strconv.ParseInt(*av[attribute].N, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return expires > 0 && expires <= now.Unix()
+}
+
+// dropExpired, removes expired items if the access filters them
+func (a *DynamoAccess) dropExpired(item interface{}, items []map[string]dynamodb.AttributeValue) ([]map[string]dynamodb.AttributeValue, error) {
+	if !a.filterExpired {
+		return items, nil
+	}
+
+	attribute, err := a.ttlAttribute(item)
+	if err != nil || attribute == "" {
+		return items, err
+	}
+
+	timeNow := now(a.clock)
+	result := make([]map[string]dynamodb.AttributeValue, 0, len(items))
+	for _, av := range items {
+		if !isExpired(av, attribute, timeNow) {
+			result = append(result, av)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,250 @@
+package godynamo
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SchemaProblem, one problem of tags declared on the field of model
+type SchemaProblem struct {
+	Field   string
+	Tag     string
+	Message string
+}
+
+func (p SchemaProblem) String() string {
+	if p.Field == "" {
+		return p.Message
+	}
+
+	if p.Tag == "" {
+		return fmt.Sprintf("field %s: %s", p.Field, p.Message)
+	}
+
+	return fmt.Sprintf("field %s (godynamo:%q): %s", p.Field, p.Tag, p.Message)
+}
+
+// SchemaError, all problems found in the schema of model
+type SchemaError struct {
+	Model    string
+	Problems []SchemaProblem
+}
+
+// Is, schema error is validation error
+func (e *SchemaError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+func (e *SchemaError) Error() string {
+	problems := make([]string, 0, len(e.Problems))
+	for _, problem := range e.Problems {
+		problems = append(problems, problem.String())
+	}
+
+	return fmt.Sprintf("invalid schema of model %s: %s", e.Model, strings.Join(problems, "; "))
+}
+
+// ValidateModel, checks tags of given model, returns *SchemaError with every problem found
+func ValidateModel(item interface{}) error {
+	t := reflect.TypeOf(item)
+	if t == nil {
+		return ErrElemNil
+	}
+
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return ErrNotSupportedType
+	}
+
+	v := &modelValidator{
+		globalIndexes: map[string]*indexDeclaration{},
+		localIndexes:  map[string]*indexDeclaration{},
+	}
+	v.fields(t)
+	v.keys()
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+
+	return &SchemaError{Model: t.Name(), Problems: v.problems}
+}
+
+// declaration, field which declares part of the schema
+type declaration struct {
+	field string
+	tag   string
+}
+
+type indexDeclaration struct {
+	hash  []declaration
+	rang  []declaration
+	order int
+}
+
+type modelValidator struct {
+	problems []SchemaProblem
+
+	hash          []declaration
+	rang          []declaration
+	ttl           []declaration
+	globalIndexes map[string]*indexDeclaration
+	localIndexes  map[string]*indexDeclaration
+}
+
+func (v *modelValidator) problem(field, tag, format string, args ...interface{}) {
+	v.problems = append(v.problems, SchemaProblem{
+		Field:   field,
+		Tag:     tag,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *modelValidator) fields(t reflect.Type) {
+	fields, problems := modelFields(t)
+	v.problems = append(v.problems, problems...)
+
+	for _, field := range fields {
+		dynamoTag, ok := field.Tag.Lookup("godynamo")
+		if !ok {
+			continue
+		}
+
+		for _, dynamoFunc := range strings.Split(dynamoTag, ",") {
+			v.function(field.StructField, field.path, dynamoFunc)
+		}
+	}
+}
+
+// function, validates one function of godynamo tag
+func (v *modelValidator) function(field reflect.StructField, name, dynamoFunc string) {
+	d := declaration{field: name, tag: dynamoFunc}
+
+	switch {
+	case dynamoFunc == "ttl":
+		switch field.Type.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			v.problem(name, dynamoFunc, "ttl attribute has to be integer with unix time, not %s", field.Type)
+		}
+		v.ttl = append(v.ttl, d)
+		return
+	case dynamoFunc == "hash":
+		v.hash = append(v.hash, d)
+	case dynamoFunc == "range":
+		v.rang = append(v.rang, d)
+	case strings.HasPrefix(dynamoFunc, "global_secondary_index("):
+		if !v.index(v.globalIndexes, d, "global_secondary_index(") {
+			return
+		}
+	case strings.HasPrefix(dynamoFunc, "local_secondary_index("):
+		if !v.index(v.localIndexes, d, "local_secondary_index(") {
+			return
+		}
+	case dynamoFunc == "":
+		v.problem(name, dynamoFunc, "empty godynamo tag")
+		return
+	default:
+		v.problem(name, dynamoFunc, "unknown godynamo tag, expected hash, range, ttl, global_secondary_index(name:hash|range) or local_secondary_index(name:range)")
+		return
+	}
+
+	// named types, e.g. type Status string, are keyed by their kind
+	if _, err := (&DynamoAccess{}).typeToScalarType(field.Type.Kind().String()); err != nil {
+		v.problem(name, dynamoFunc, "key attribute has to be string or integer, not %s", field.Type)
+	}
+}
+
+// index, parses declaration of index key, returns false when the tag is malformed
+func (v *modelValidator) index(indexes map[string]*indexDeclaration, d declaration, prefix string) bool {
+	if !strings.HasSuffix(d.tag, ")") {
+		v.problem(d.field, d.tag, "missing closing parenthesis")
+		return false
+	}
+
+	dynamoTags := strings.Split(strings.TrimSuffix(strings.TrimPrefix(d.tag, prefix), ")"), ":")
+	if len(dynamoTags) != 2 {
+		v.problem(d.field, d.tag, "index has to be declared as %sname:hash) or %sname:range)", prefix, prefix)
+		return false
+	}
+
+	if dynamoTags[0] == "" {
+		v.problem(d.field, d.tag, "missing name of index")
+		return false
+	}
+
+	index, ok := indexes[dynamoTags[0]]
+	if !ok {
+		index = &indexDeclaration{order: len(indexes)}
+		indexes[dynamoTags[0]] = index
+	}
+
+	switch dynamoTags[1] {
+	case "hash":
+		index.hash = append(index.hash, d)
+	case "range":
+		index.rang = append(index.rang, d)
+	default:
+		v.problem(d.field, d.tag, "unknown key type %q of index, expected hash or range", dynamoTags[1])
+		return false
+	}
+
+	return true
+}
+
+// keys, validates declared key schemas of the table and indexes
+func (v *modelValidator) keys() {
+	if len(v.hash) == 0 {
+		v.problem("", "", "missing hash key, tag one field as godynamo:\"hash\"")
+	}
+
+	v.duplicates(v.hash, "hash key")
+	v.duplicates(v.rang, "range key")
+	v.duplicates(v.ttl, "ttl attribute")
+
+	for _, name := range indexNames(v.globalIndexes) {
+		index := v.globalIndexes[name]
+		if len(index.hash) == 0 {
+			v.problem("", "", "global secondary index %s has no hash key", name)
+		}
+		v.duplicates(index.hash, "hash key of global secondary index "+name)
+		v.duplicates(index.rang, "range key of global secondary index "+name)
+	}
+
+	for _, name := range indexNames(v.localIndexes) {
+		index := v.localIndexes[name]
+		for _, d := range index.hash {
+			v.problem(d.field, d.tag, "local secondary index %s uses hash key of the table, declare only its range key", name)
+		}
+		if len(index.rang) == 0 {
+			v.problem("", "", "local secondary index %s has no range key", name)
+		}
+		if len(v.rang) == 0 {
+			for _, d := range index.rang {
+				v.problem(d.field, d.tag, "local secondary index %s requires range key of the table", name)
+			}
+		}
+		v.duplicates(index.rang, "range key of local secondary index "+name)
+	}
+}
+
+func (v *modelValidator) duplicates(declarations []declaration, what string) {
+	for i := 1; i < len(declarations); i++ {
+		v.problem(declarations[i].field, declarations[i].tag, "duplicate %s, already declared by field %s", what, declarations[0].field)
+	}
+}
+
+// indexNames, names of indexes in order of declaration
+func indexNames(indexes map[string]*indexDeclaration) []string {
+	names := make([]string, len(indexes))
+	for name, index := range indexes {
+		names[index.order] = name
+	}
+
+	return names
+}
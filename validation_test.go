@@ -0,0 +1,129 @@
+package godynamo
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type noHash struct {
+	Name string `json:"name" godynamo:"range"`
+}
+
+type duplicateHash struct {
+	Model
+
+	Email string `json:"email" godynamo:"hash"`
+}
+
+type localIndexWithoutRange struct {
+	Model
+
+	Name string `json:"name" godynamo:"local_secondary_index(index:range)"`
+}
+
+type malformedIndex struct {
+	Model
+
+	Name  string  `json:"name" godynamo:"global_secondary_index(index)"`
+	Other string  `json:"other" godynamo:"global_secondary_index(other:sort)"`
+	Price float64 `json:"price" godynamo:"range"`
+	Flag  string  `json:"-" godynamo:"hash"`
+	Kind  string  `dynamodbav:"kind" godynamo:"hash_key"`
+}
+
+type status string
+
+// localIndexFirst, local secondary index declared before hash key of the table, range key of named type
+type localIndexFirst struct {
+	Sorted string `json:"sorted" godynamo:"local_secondary_index(index:range)"`
+	Key    string `json:"key" godynamo:"hash"`
+	Status status `json:"status" godynamo:"range"`
+}
+
+type SchemaValidationSuite struct {
+	suite.Suite
+}
+
+func (t *SchemaValidationSuite) TestValidModels() {
+	for _, item := range []interface{}{&aaa{}, &bbb{}, &ccc{}, &ddd{}, &eee{}, &fff{}, &ggg{}, &hhh{}, &iii{}, &user{}, &[]user{}} {
+		t.Nil(ValidateModel(item))
+	}
+}
+
+func (t *SchemaValidationSuite) TestNoHash() {
+	err := ValidateModel(&noHash{})
+
+	t.Equal(&SchemaError{
+		Model: "noHash",
+		Problems: []SchemaProblem{
+			{Message: "missing hash key, tag one field as godynamo:\"hash\""},
+		},
+	}, err)
+}
+
+func (t *SchemaValidationSuite) TestDuplicateHash() {
+	err := ValidateModel(&duplicateHash{})
+
+	t.Equal(&SchemaError{
+		Model: "duplicateHash",
+		Problems: []SchemaProblem{
+			{Field: "Email", Tag: "hash", Message: "duplicate hash key, already declared by field Model.Id"},
+		},
+	}, err)
+}
+
+func (t *SchemaValidationSuite) TestLocalIndexWithoutRange() {
+	err := ValidateModel(&localIndexWithoutRange{})
+
+	t.Equal(&SchemaError{
+		Model: "localIndexWithoutRange",
+		Problems: []SchemaProblem{
+			{Field: "Name", Tag: "local_secondary_index(index:range)", Message: "local secondary index index requires range key of the table"},
+		},
+	}, err)
+}
+
+func (t *SchemaValidationSuite) TestMalformedTags() {
+	err := ValidateModel(&malformedIndex{})
+
+	schemaErr, ok := err.(*SchemaError)
+	t.True(ok)
+	t.Equal([]SchemaProblem{
+		{Field: "Flag", Tag: "hash", Message: "field is not marshaled, it is unexported or its tag is \"-\""},
+		{Field: "Name", Tag: "global_secondary_index(index)", Message: "index has to be declared as global_secondary_index(name:hash) or global_secondary_index(name:range)"},
+		{Field: "Other", Tag: "global_secondary_index(other:sort)", Message: "unknown key type \"sort\" of index, expected hash or range"},
+		{Field: "Price", Tag: "range", Message: "key attribute has to be string or integer, not float64"},
+		{Field: "Kind", Tag: "hash_key", Message: "unknown godynamo tag, expected hash, range, ttl, global_secondary_index(name:hash|range) or local_secondary_index(name:range)"},
+		{Message: "global secondary index other has no hash key"},
+	}, schemaErr.Problems)
+
+	t.Contains(err.Error(), "invalid schema of model malformedIndex: field Flag (godynamo:\"hash\")")
+}
+
+func (t *SchemaValidationSuite) TestLocalIndexBeforeHash() {
+	t.Nil(ValidateModel(&localIndexFirst{}))
+
+	input, err := (&DynamoAccess{tablePrefix: "validation_"}).tableInput(&localIndexFirst{})
+	t.Nil(err)
+	t.Len(input.LocalSecondaryIndexes, 1)
+	t.Equal("key HASH, sorted RANGE", keySchemaString(input.LocalSecondaryIndexes[0].KeySchema))
+	t.Equal("key HASH, status RANGE", keySchemaString(input.KeySchema))
+	t.Contains(input.AttributeDefinitions, dynamodb.AttributeDefinition{
+		AttributeName: aws.String("status"),
+		AttributeType: dynamodb.ScalarAttributeTypeS,
+	})
+}
+
+func (t *SchemaValidationSuite) TestCreateTablesValidates() {
+	access := &DynamoAccess{tablePrefix: "validation_"}
+
+	errs := access.CreateTables(&noHash{})
+	t.Len(errs, 1)
+	t.IsType(&SchemaError{}, errs[0])
+}
+
+func TestSchemaValidationSuite(t *testing.T) {
+	suite.Run(t, &SchemaValidationSuite{})
+}